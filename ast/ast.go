@@ -1,13 +1,15 @@
 package ast
 
 import (
-	"wordlang/token"
+	"reflect"
 	"strings"
+	"wordlang/token"
 )
 
 // Node is the base interface for all nodes in the AST.
 type Node interface {
 	TokenLiteral() string // For debugging and testing
+	Pos() token.Token     // The node's first token, which gives its source position
 	String() string       // For pretty printing the AST
 }
 
@@ -23,6 +25,15 @@ type Expression interface {
 	expressionNode()
 }
 
+// nodeString renders a child node, tolerating the children that a failed
+// parse leaves unset in partial nodes.
+func nodeString(n Node) string {
+	if n == nil || reflect.ValueOf(n).IsNil() {
+		return "<missing>"
+	}
+	return n.String()
+}
+
 // Program is the root node of the AST.
 type Program struct {
 	Statements []Statement
@@ -35,6 +46,9 @@ func (p *Program) TokenLiteral() string {
 	return ""
 }
 
+// Pos returns the zero Token: a program has no single position.
+func (p *Program) Pos() token.Token { return token.Token{} }
+
 func (p *Program) String() string {
 	var out string
 	for _, s := range p.Statements {
@@ -49,21 +63,58 @@ type Identifier struct {
 }
 func (i *Identifier) expressionNode()    {}
 func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
+func (i *Identifier) Pos() token.Token     { return i.Token }
 func (i *Identifier) String() string       { return i.Value }
 
-// LetStatement represents a 'let' statement.
+// LetStatement represents a 'let' statement. It declares a name in the
+// current scope, hiding any binding of the same name in enclosing scopes.
 type LetStatement struct {
 	Token token.Token // The 'let' token
 	Name  *Identifier
 	Value Expression
+	Doc   string // Text of the '##' comment lines above the statement, if any
 }
 func (ls *LetStatement) statementNode() {}
 func (ls *LetStatement) TokenLiteral() string { return ls.Token.Literal }
+func (ls *LetStatement) Pos() token.Token     { return ls.Token }
 func (ls *LetStatement) String() string {
-	return ls.TokenLiteral() + " " + ls.Name.String() + " be " + ls.Value.String()
+	return ls.TokenLiteral() + " " + nodeString(ls.Name) + " be " + nodeString(ls.Value)
 }
 
 
+// BadStatement marks a statement that failed to parse. Partial holds what
+// was parsed before the error, if anything, so tools can keep working.
+type BadStatement struct {
+	Token   token.Token // The first token of the statement
+	Partial Statement
+}
+
+func (bs *BadStatement) statementNode()     {}
+func (bs *BadStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BadStatement) Pos() token.Token     { return bs.Token }
+func (bs *BadStatement) String() string {
+	if bs.Partial == nil {
+		return "<bad statement>"
+	}
+	return "<bad statement: " + nodeString(bs.Partial) + ">"
+}
+
+// BadExpression marks an expression that failed to parse.
+type BadExpression struct {
+	Token   token.Token // The token where the expression failed
+	Partial Expression
+}
+
+func (be *BadExpression) expressionNode()    {}
+func (be *BadExpression) TokenLiteral() string { return be.Token.Literal }
+func (be *BadExpression) Pos() token.Token     { return be.Token }
+func (be *BadExpression) String() string {
+	if be.Partial == nil {
+		return "<bad expression>"
+	}
+	return "<bad expression: " + nodeString(be.Partial) + ">"
+}
+
 // ReturnStatement represents a 'return' statement.
 type ReturnStatement struct {
 	Token token.Token // The 'return' token
@@ -71,8 +122,9 @@ type ReturnStatement struct {
 }
 func (rs *ReturnStatement) statementNode()     {}
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
+func (rs *ReturnStatement) Pos() token.Token     { return rs.Token }
 func (rs *ReturnStatement) String() string {
-	return rs.TokenLiteral() + " " + rs.ReturnValue.String()
+	return rs.TokenLiteral() + " " + nodeString(rs.ReturnValue)
 }
 
 // ExpressionStatement wraps an expression to be used as a statement.
@@ -82,9 +134,10 @@ type ExpressionStatement struct {
 }
 func (es *ExpressionStatement) statementNode()     {}
 func (es *ExpressionStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExpressionStatement) Pos() token.Token     { return es.Token }
 func (es *ExpressionStatement) String() string {
 	if es.Expression != nil {
-		return es.Expression.String()
+		return nodeString(es.Expression)
 	}
 	return ""
 }
@@ -97,6 +150,7 @@ type IntegerLiteral struct {
 
 func (il *IntegerLiteral) expressionNode()    {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
+func (il *IntegerLiteral) Pos() token.Token     { return il.Token }
 func (il *IntegerLiteral) String() string       { return il.Token.Literal }
 
 // FloatLiteral represents a floating-point literal.
@@ -107,6 +161,7 @@ type FloatLiteral struct {
 
 func (fl *FloatLiteral) expressionNode()    {}
 func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) Pos() token.Token     { return fl.Token }
 func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
 
 // StringLiteral represents a string literal.
@@ -117,8 +172,34 @@ type StringLiteral struct {
 
 func (sl *StringLiteral) expressionNode()    {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *StringLiteral) Pos() token.Token     { return sl.Token }
 func (sl *StringLiteral) String() string       { return sl.Token.Literal }
 
+// InterpolatedString represents a string literal with embedded expressions,
+// like "Hello {name}". Parts holds StringLiterals for the text between the
+// braces and the parsed expressions, in order.
+type InterpolatedString struct {
+	Token token.Token // The INTERPOLATED token
+	Parts []Expression
+}
+
+func (ip *InterpolatedString) expressionNode()      {}
+func (ip *InterpolatedString) TokenLiteral() string { return ip.Token.Literal }
+func (ip *InterpolatedString) Pos() token.Token     { return ip.Token }
+func (ip *InterpolatedString) String() string {
+	var out strings.Builder
+	out.WriteString("\"")
+	for _, part := range ip.Parts {
+		if sl, ok := part.(*StringLiteral); ok {
+			out.WriteString(sl.Value)
+			continue
+		}
+		out.WriteString("{" + nodeString(part) + "}")
+	}
+	out.WriteString("\"")
+	return out.String()
+}
+
 // BooleanLiteral represents a boolean literal (true or false).
 type BooleanLiteral struct {
 	Token token.Token // The boolean token (TRUE or FALSE)
@@ -127,6 +208,7 @@ type BooleanLiteral struct {
 
 func (bl *BooleanLiteral) expressionNode()    {}
 func (bl *BooleanLiteral) TokenLiteral() string { return bl.Token.Literal }
+func (bl *BooleanLiteral) Pos() token.Token     { return bl.Token }
 func (bl *BooleanLiteral) String() string       { return bl.Token.Literal }
 
 // PrefixExpression represents a prefix operator expression (e.g., 'not condition').
@@ -138,8 +220,9 @@ type PrefixExpression struct {
 
 func (pe *PrefixExpression) expressionNode()    {}
 func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PrefixExpression) Pos() token.Token     { return pe.Token }
 func (pe *PrefixExpression) String() string {
-	return "(" + pe.Operator + pe.Right.String() + ")"
+	return "(" + pe.Operator + " " + nodeString(pe.Right) + ")"
 }
 
 // InfixExpression represents an infix operator expression (e.g., 'add a and b').
@@ -148,12 +231,17 @@ type InfixExpression struct {
 	Left     Expression
 	Operator string
 	Right    Expression
+	InPlace  bool // 'add 5 to x': Left is the variable that receives the result
 }
 
 func (oe *InfixExpression) expressionNode()    {}
 func (oe *InfixExpression) TokenLiteral() string { return oe.Token.Literal }
+func (oe *InfixExpression) Pos() token.Token     { return oe.Token }
 func (oe *InfixExpression) String() string {
-	return "(" + oe.Left.String() + " " + oe.Operator + " " + oe.Right.String() + ")"
+	if oe.InPlace {
+		return "(" + oe.Operator + " " + nodeString(oe.Right) + " to " + nodeString(oe.Left) + ")"
+	}
+	return "(" + nodeString(oe.Left) + " " + oe.Operator + " " + nodeString(oe.Right) + ")"
 }
 
 // IfStatement represents an 'if' statement.
@@ -167,14 +255,15 @@ type IfStatement struct {
 
 func (is *IfStatement) statementNode()     {}
 func (is *IfStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *IfStatement) Pos() token.Token     { return is.Token }
 func (is *IfStatement) String() string {
 	var out string
-	out += "if " + is.Condition.String() + " then " + is.ThenBlock.String()
+	out += "if " + nodeString(is.Condition) + " then " + nodeString(is.ThenBlock)
 	for _, elseifBlock := range is.ElseIfBlocks {
-		out += " elseif " + elseifBlock.Condition.String() + " then " + elseifBlock.Block.String()
+		out += " elseif " + nodeString(elseifBlock.Condition) + " then " + nodeString(elseifBlock.Block)
 	}
 	if is.ElseBlock != nil {
-		out += " else " + is.ElseBlock.String()
+		out += " else " + nodeString(is.ElseBlock)
 	}
 	out += " endif"
 	return out
@@ -194,11 +283,12 @@ type BlockStatement struct {
 
 func (bs *BlockStatement) statementNode()     {}
 func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) Pos() token.Token     { return bs.Token }
 func (bs *BlockStatement) String() string {
 	var out string
 	out += "{\n" // For visual representation of blocks
 	for _, s := range bs.Statements {
-		out += "  " + s.String() + "\n" // Indent for block content
+		out += "  " + nodeString(s) + "\n" // Indent for block content
 	}
 	out += "}\n" // End of block
 	return out
@@ -209,27 +299,320 @@ func (bs *BlockStatement) String() string {
 type WhileStatement struct {
 	Token     token.Token // The 'while' token
 	Condition Expression
+	Label     *Identifier // Optional: 'named outer', for 'stop outer' and 'skip outer'
 	Body      *BlockStatement
 }
 
 func (ws *WhileStatement) statementNode()     {}
 func (ws *WhileStatement) TokenLiteral() string { return ws.Token.Literal }
+func (ws *WhileStatement) Pos() token.Token     { return ws.Token }
 func (ws *WhileStatement) String() string {
-	return "while " + ws.Condition.String() + " do " + ws.Body.String() + " endwhile"
+	return "while " + nodeString(ws.Condition) + labelString(ws.Label) + " do " + nodeString(ws.Body) + " endwhile"
 }
 
 // ForEachStatement represents a 'for each' loop.
 type ForEachStatement struct {
 	Token    token.Token // The 'foreach' token
 	Variable *Identifier
-	Iterable Expression // Expression that should evaluate to a list
+	Iterable Expression  // Expression that should evaluate to a list
+	Label    *Identifier // Optional loop label
 	Body     *BlockStatement
 }
 
 func (fes *ForEachStatement) statementNode()     {}
 func (fes *ForEachStatement) TokenLiteral() string { return fes.Token.Literal }
+func (fes *ForEachStatement) Pos() token.Token     { return fes.Token }
 func (fes *ForEachStatement) String() string {
-	return "foreach " + fes.Variable.String() + " in " + fes.Iterable.String() + " do " + fes.Body.String() + " endforeach"
+	return "foreach " + nodeString(fes.Variable) + " in " + nodeString(fes.Iterable) + labelString(fes.Label) + " do " + nodeString(fes.Body) + " endforeach"
+}
+
+// RepeatStatement represents a counted loop: 'repeat 5 times do ... endrepeat'.
+type RepeatStatement struct {
+	Token token.Token // The 'repeat' token
+	Count Expression
+	Label *Identifier // Optional loop label
+	Body  *BlockStatement
+}
+
+func (rs *RepeatStatement) statementNode()       {}
+func (rs *RepeatStatement) TokenLiteral() string { return rs.Token.Literal }
+func (rs *RepeatStatement) Pos() token.Token     { return rs.Token }
+func (rs *RepeatStatement) String() string {
+	return "repeat " + nodeString(rs.Count) + " times" + labelString(rs.Label) + " do " + nodeString(rs.Body) + " endrepeat"
+}
+
+// ForStatement represents a loop over a range of numbers:
+// 'for i from 1 to 10 step 2 do ... endfor'. Both ends are included.
+type ForStatement struct {
+	Token    token.Token // The 'for' token
+	Variable *Identifier
+	Start    Expression
+	End      Expression
+	Step     Expression  // Optional; 1, or -1 for a descending range
+	Label    *Identifier // Optional loop label
+	Body     *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForStatement) Pos() token.Token     { return fs.Token }
+func (fs *ForStatement) String() string {
+	step := ""
+	if fs.Step != nil {
+		step = " step " + nodeString(fs.Step)
+	}
+	return "for " + nodeString(fs.Variable) + " from " + nodeString(fs.Start) + " to " + nodeString(fs.End) + step +
+		labelString(fs.Label) + " do " + nodeString(fs.Body) + " endfor"
+}
+
+// IncrementStatement represents 'increment count by 5' and 'decrement total',
+// and the list form 'increment item at index 2 of scores'.
+type IncrementStatement struct {
+	Token  token.Token // The 'increment' or 'decrement' token
+	Name   *Identifier // The variable, or the list holding the item
+	Index  Expression  // Set for the list form
+	Amount Expression  // Optional 'by' amount; 1 if nil
+}
+
+func (is *IncrementStatement) statementNode()       {}
+func (is *IncrementStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *IncrementStatement) Pos() token.Token     { return is.Token }
+func (is *IncrementStatement) String() string {
+	var out strings.Builder
+	out.WriteString(is.Token.Literal + " ")
+	if is.Index != nil {
+		out.WriteString("item at index " + nodeString(is.Index) + " of ")
+	}
+	out.WriteString(nodeString(is.Name))
+	if is.Amount != nil {
+		out.WriteString(" by " + nodeString(is.Amount))
+	}
+	return out.String()
+}
+
+// SetStatement represents 'set total to 5', which assigns to an existing
+// binding in the scope that declared it.
+type SetStatement struct {
+	Token token.Token // The 'set' token
+	Name  *Identifier
+	Value Expression
+}
+
+func (ss *SetStatement) statementNode()       {}
+func (ss *SetStatement) TokenLiteral() string { return ss.Token.Literal }
+func (ss *SetStatement) Pos() token.Token     { return ss.Token }
+func (ss *SetStatement) String() string {
+	return "set " + nodeString(ss.Name) + " to " + nodeString(ss.Value)
+}
+
+// WhenStatement represents multi-way branching on one value:
+// 'when x is 1 or 2 ... is between 3 and 10 ... otherwise ... endwhen'.
+type WhenStatement struct {
+	Token     token.Token // The 'when' token
+	Subject   Expression
+	Arms      []*WhenArm
+	Otherwise *BlockStatement // Optional
+}
+
+func (ws *WhenStatement) statementNode()       {}
+func (ws *WhenStatement) TokenLiteral() string { return ws.Token.Literal }
+func (ws *WhenStatement) Pos() token.Token     { return ws.Token }
+func (ws *WhenStatement) String() string {
+	var out strings.Builder
+	out.WriteString("when " + nodeString(ws.Subject))
+	for _, arm := range ws.Arms {
+		out.WriteString(" " + arm.String())
+	}
+	if ws.Otherwise != nil {
+		out.WriteString(" otherwise " + nodeString(ws.Otherwise))
+	}
+	out.WriteString(" endwhen")
+	return out.String()
+}
+
+// WhenArm is one 'is' arm of a WhenStatement. It matches either one of
+// Values, or, for 'is between Low and High', any number in that range.
+type WhenArm struct {
+	Token  token.Token // The 'is' token
+	Values []Expression
+	Low    Expression // Set for 'is between'
+	High   Expression
+	Body   *BlockStatement
+}
+
+func (wa *WhenArm) String() string {
+	if wa.Low != nil {
+		return "is between " + nodeString(wa.Low) + " and " + nodeString(wa.High) + " " + nodeString(wa.Body)
+	}
+	values := make([]string, len(wa.Values))
+	for i, v := range wa.Values {
+		values[i] = nodeString(v)
+	}
+	return "is " + strings.Join(values, " or ") + " " + nodeString(wa.Body)
+}
+
+// TryStatement represents 'try ... if it fails as err ... always ... endtry'.
+// Handler runs if Body fails with a runtime error, with its message bound to
+// ErrorName; Always runs last either way.
+type TryStatement struct {
+	Token     token.Token // The 'try' token
+	Body      *BlockStatement
+	ErrorName *Identifier     // Optional 'as' name
+	Handler   *BlockStatement // Optional 'if it fails' block
+	Always    *BlockStatement // Optional 'always' block
+}
+
+func (ts *TryStatement) statementNode()       {}
+func (ts *TryStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *TryStatement) Pos() token.Token     { return ts.Token }
+func (ts *TryStatement) String() string {
+	var out strings.Builder
+	out.WriteString("try " + nodeString(ts.Body))
+	if ts.Handler != nil {
+		out.WriteString(" if it fails")
+		if ts.ErrorName != nil {
+			out.WriteString(" as " + ts.ErrorName.Value)
+		}
+		out.WriteString(" " + nodeString(ts.Handler))
+	}
+	if ts.Always != nil {
+		out.WriteString(" always " + nodeString(ts.Always))
+	}
+	out.WriteString(" endtry")
+	return out.String()
+}
+
+// RaiseStatement represents 'raise "message"', which fails with a runtime error.
+type RaiseStatement struct {
+	Token   token.Token // The 'raise' token
+	Message Expression
+}
+
+func (rs *RaiseStatement) statementNode()       {}
+func (rs *RaiseStatement) TokenLiteral() string { return rs.Token.Literal }
+func (rs *RaiseStatement) Pos() token.Token     { return rs.Token }
+func (rs *RaiseStatement) String() string       { return "raise " + nodeString(rs.Message) }
+
+// SetValueStatement represents 'set value for "carol" in scores to 70', which
+// adds or replaces an entry of a dictionary.
+type SetValueStatement struct {
+	Token      token.Token // The 'set' token
+	Key        Expression
+	Dictionary Expression
+	Value      Expression
+}
+
+func (svs *SetValueStatement) statementNode()       {}
+func (svs *SetValueStatement) TokenLiteral() string { return svs.Token.Literal }
+func (svs *SetValueStatement) Pos() token.Token     { return svs.Token }
+func (svs *SetValueStatement) String() string {
+	return "set value for " + nodeString(svs.Key) + " in " + nodeString(svs.Dictionary) + " to " + nodeString(svs.Value)
+}
+
+// RemoveStatement represents 'remove "bob" from scores', which removes a key
+// from a dictionary or the first equal item from a list, and
+// 'remove item at index 0 from names'.
+type RemoveStatement struct {
+	Token token.Token // The 'remove' token
+	Value Expression  // Set for removing a key or a value
+	Index Expression  // Set for removing by position
+	From  Expression
+}
+
+func (rs *RemoveStatement) statementNode()       {}
+func (rs *RemoveStatement) TokenLiteral() string { return rs.Token.Literal }
+func (rs *RemoveStatement) Pos() token.Token     { return rs.Token }
+func (rs *RemoveStatement) String() string {
+	if rs.Index != nil {
+		return "remove item at index " + nodeString(rs.Index) + " from " + nodeString(rs.From)
+	}
+	return "remove " + nodeString(rs.Value) + " from " + nodeString(rs.From)
+}
+
+// AppendStatement represents 'append "Dan" to names'.
+type AppendStatement struct {
+	Token token.Token // The 'append' token
+	Value Expression
+	List  Expression
+}
+
+func (as *AppendStatement) statementNode()       {}
+func (as *AppendStatement) TokenLiteral() string { return as.Token.Literal }
+func (as *AppendStatement) Pos() token.Token     { return as.Token }
+func (as *AppendStatement) String() string {
+	return "append " + nodeString(as.Value) + " to " + nodeString(as.List)
+}
+
+// InsertStatement represents 'insert "Dan" at index 2 of names'. The item
+// at that index and those after it move up by one.
+type InsertStatement struct {
+	Token token.Token // The 'insert' token
+	Value Expression
+	Index Expression
+	List  Expression
+}
+
+func (is *InsertStatement) statementNode()       {}
+func (is *InsertStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *InsertStatement) Pos() token.Token     { return is.Token }
+func (is *InsertStatement) String() string {
+	return "insert " + nodeString(is.Value) + " at index " + nodeString(is.Index) + " of " + nodeString(is.List)
+}
+
+// SetItemStatement represents 'set item at index 1 of names to "Zed"'.
+type SetItemStatement struct {
+	Token token.Token // The 'set' token
+	Index Expression
+	List  Expression
+	Value Expression
+}
+
+func (sis *SetItemStatement) statementNode()       {}
+func (sis *SetItemStatement) TokenLiteral() string { return sis.Token.Literal }
+func (sis *SetItemStatement) Pos() token.Token     { return sis.Token }
+func (sis *SetItemStatement) String() string {
+	return "set item at index " + nodeString(sis.Index) + " of " + nodeString(sis.List) + " to " + nodeString(sis.Value)
+}
+
+// labelString renders an optional loop label as it is written after the loop header.
+func labelString(label *Identifier) string {
+	if label == nil {
+		return ""
+	}
+	return " named " + label.Value
+}
+
+// StopStatement leaves the innermost loop, or the loop with the given label.
+type StopStatement struct {
+	Token token.Token // The 'stop' token
+	Label *Identifier // Optional
+}
+
+func (ss *StopStatement) statementNode()       {}
+func (ss *StopStatement) TokenLiteral() string { return ss.Token.Literal }
+func (ss *StopStatement) Pos() token.Token     { return ss.Token }
+func (ss *StopStatement) String() string {
+	if ss.Label != nil {
+		return "stop " + ss.Label.Value
+	}
+	return "stop"
+}
+
+// SkipStatement moves on to the next iteration of the innermost loop, or of
+// the loop with the given label.
+type SkipStatement struct {
+	Token token.Token // The 'skip' token
+	Label *Identifier // Optional
+}
+
+func (ss *SkipStatement) statementNode()       {}
+func (ss *SkipStatement) TokenLiteral() string { return ss.Token.Literal }
+func (ss *SkipStatement) Pos() token.Token     { return ss.Token }
+func (ss *SkipStatement) String() string {
+	if ss.Label != nil {
+		return "skip " + ss.Label.Value
+	}
+	return "skip"
 }
 
 // FunctionLiteral represents a function definition.
@@ -241,14 +624,29 @@ type FunctionLiteral struct {
 
 func (fl *FunctionLiteral) expressionNode()    {} // Functions are expressions in some contexts (e.g., function literals)
 func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FunctionLiteral) Pos() token.Token     { return fl.Token }
 func (fl *FunctionLiteral) String() string {
 	params := []string{}
 	for _, p := range fl.Parameters {
-		params = append(params, p.String())
+		params = append(params, nodeString(p))
 	}
-	return "function(" + strings.Join(params, ", ") + ") " + fl.Body.String() + " end function"
+	return "function(" + strings.Join(params, ", ") + ") " + nodeString(fl.Body) + " end function"
 }
 
+// FunctionStatement represents a named function declaration: 'function greet person ... endfunction'.
+type FunctionStatement struct {
+	Token    token.Token // The 'function' token
+	Name     *Identifier
+	Function *FunctionLiteral
+	Doc      string // Text of the '##' comment lines above the function, if any
+}
+
+func (fs *FunctionStatement) statementNode()     {}
+func (fs *FunctionStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *FunctionStatement) Pos() token.Token     { return fs.Token }
+func (fs *FunctionStatement) String() string {
+	return "function " + nodeString(fs.Name) + " " + nodeString(fs.Function)
+}
 
 // CallExpression represents a function call.
 type CallExpression struct {
@@ -259,12 +657,13 @@ type CallExpression struct {
 
 func (ce *CallExpression) expressionNode()    {}
 func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CallExpression) Pos() token.Token     { return ce.Token }
 func (ce *CallExpression) String() string {
 	args := []string{}
 	for _, a := range ce.Arguments {
-		args = append(args, a.String())
+		args = append(args, nodeString(a))
 	}
-	return "call " + ce.Function.String() + "(" + strings.Join(args, ", ") + ")" // Parentheses for arguments for now, might reconsider
+	return "call " + nodeString(ce.Function) + "(" + strings.Join(args, ", ") + ")" // Parentheses for arguments for now, might reconsider
 }
 
 // PrintStatement represents a 'print' statement.
@@ -275,8 +674,9 @@ type PrintStatement struct {
 
 func (ps *PrintStatement) statementNode()     {}
 func (ps *PrintStatement) TokenLiteral() string { return ps.Token.Literal }
+func (ps *PrintStatement) Pos() token.Token     { return ps.Token }
 func (ps *PrintStatement) String() string {
-	return "print " + ps.Value.String()
+	return "print " + nodeString(ps.Value)
 }
 
 // InputStatement represents an 'input' statement.
@@ -287,27 +687,65 @@ type InputStatement struct {
 
 func (is *InputStatement) statementNode() {}
 func (is *InputStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *InputStatement) Pos() token.Token     { return is.Token }
 func (is *InputStatement) String() string {
 	if is.Prompt != nil {
-		return "input " + is.Prompt.String()
+		return "input " + nodeString(is.Prompt)
 	}
 	return "input"
 }
 
 // ListLiteral represents a list literal.
 type ListLiteral struct {
-	Token    token.Token // The 'list' token
+	Token    token.Token // The 'list' token, or 'strings', 'numbers' or 'decimals' for a typed list
 	Elements []Expression
 }
 
 func (ll *ListLiteral) expressionNode()    {}
 func (ll *ListLiteral) TokenLiteral() string { return ll.Token.Literal }
+func (ll *ListLiteral) Pos() token.Token     { return ll.Token }
 func (ll *ListLiteral) String() string {
 	elems := []string{}
 	for _, el := range ll.Elements {
-		elems = append(elems, el.String())
+		elems = append(elems, nodeString(el))
 	}
-	return "list(" + strings.Join(elems, ", ") + ")" // Parentheses for list elements for now, reconsider
+	return ll.Token.Literal + "(" + strings.Join(elems, ", ") + ")" // Parentheses for list elements for now, reconsider
+}
+
+// DictionaryLiteral represents 'dictionary with "alice" as 90 and "bob" as 85'.
+// A bare 'dictionary' is empty.
+type DictionaryLiteral struct {
+	Token  token.Token // The 'dictionary' token
+	Keys   []Expression
+	Values []Expression // Values[i] belongs to Keys[i]
+}
+
+func (dl *DictionaryLiteral) expressionNode()      {}
+func (dl *DictionaryLiteral) TokenLiteral() string { return dl.Token.Literal }
+func (dl *DictionaryLiteral) Pos() token.Token     { return dl.Token }
+func (dl *DictionaryLiteral) String() string {
+	if len(dl.Keys) == 0 {
+		return "dictionary"
+	}
+	pairs := make([]string, len(dl.Keys))
+	for i := range dl.Keys {
+		pairs[i] = nodeString(dl.Keys[i]) + " as " + nodeString(dl.Values[i])
+	}
+	return "dictionary with " + strings.Join(pairs, " and ")
+}
+
+// ValueForExpression represents looking up a key: 'value for "alice" in scores'.
+type ValueForExpression struct {
+	Token      token.Token // The 'value for' token
+	Key        Expression
+	Dictionary Expression
+}
+
+func (vfe *ValueForExpression) expressionNode()      {}
+func (vfe *ValueForExpression) TokenLiteral() string { return vfe.Token.Literal }
+func (vfe *ValueForExpression) Pos() token.Token     { return vfe.Token }
+func (vfe *ValueForExpression) String() string {
+	return "value for " + nodeString(vfe.Key) + " in " + nodeString(vfe.Dictionary)
 }
 
 // GetItemAtIndexExpression represents getting an item from a list at a specific index.
@@ -319,8 +757,9 @@ type GetItemAtIndexExpression struct {
 
 func (giae *GetItemAtIndexExpression) expressionNode()    {}
 func (giae *GetItemAtIndexExpression) TokenLiteral() string { return giae.Token.Literal }
+func (giae *GetItemAtIndexExpression) Pos() token.Token     { return giae.Token }
 func (giae *GetItemAtIndexExpression) String() string {
-	return "get item at index " + giae.Index.String() + " from " + giae.List.String()
+	return "get item at index " + nodeString(giae.Index) + " from " + nodeString(giae.List)
 }
 
 // IsDefinedExpression checks if a variable is defined.
@@ -331,8 +770,9 @@ type IsDefinedExpression struct {
 
 func (ide *IsDefinedExpression) expressionNode() {}
 func (ide *IsDefinedExpression) TokenLiteral() string { return ide.Token.Literal }
+func (ide *IsDefinedExpression) Pos() token.Token     { return ide.Token }
 func (ide *IsDefinedExpression) String() string {
-	return "is defined " + ide.Identifier.String()
+	return "is defined " + nodeString(ide.Identifier)
 }
 
 // ExitStatement represents the 'exit' statement.
@@ -343,9 +783,10 @@ type ExitStatement struct {
 
 func (es *ExitStatement) statementNode() {}
 func (es *ExitStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExitStatement) Pos() token.Token     { return es.Token }
 func (es *ExitStatement) String() string {
 	if es.Code != nil {
-		return "exit " + es.Code.String()
+		return "exit " + nodeString(es.Code)
 	}
 	return "exit"
 }
@@ -358,8 +799,9 @@ type ConvertToNumberExpression struct {
 
 func (ctne *ConvertToNumberExpression) expressionNode() {}
 func (ctne *ConvertToNumberExpression) TokenLiteral() string { return ctne.Token.Literal }
+func (ctne *ConvertToNumberExpression) Pos() token.Token     { return ctne.Token }
 func (ctne *ConvertToNumberExpression) String() string {
-	return "convert to number " + ctne.Expression.String()
+	return "convert to number " + nodeString(ctne.Expression)
 }
 
 // ConvertToStringExpression represents converting an expression to a string.
@@ -370,6 +812,26 @@ type ConvertToStringExpression struct {
 
 func (ctse *ConvertToStringExpression) expressionNode() {}
 func (ctse *ConvertToStringExpression) TokenLiteral() string { return ctse.Token.Literal }
+func (ctse *ConvertToStringExpression) Pos() token.Token     { return ctse.Token }
 func (ctse *ConvertToStringExpression) String() string {
-	return "convert to string " + ctse.Expression.String()
+	return "convert to string " + nodeString(ctse.Expression)
+}
+
+
+// ImportStatement represents 'from <module> import <name> and <name>'.
+type ImportStatement struct {
+	Token  token.Token // The 'from' token
+	Module string      // Module name ('stdio', 'helpers') or file path ("lib/helpers.wl")
+	Names  []*Identifier
+}
+
+func (is *ImportStatement) statementNode()     {}
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImportStatement) Pos() token.Token     { return is.Token }
+func (is *ImportStatement) String() string {
+	names := []string{}
+	for _, n := range is.Names {
+		names = append(names, nodeString(n))
+	}
+	return "from " + is.Module + " import " + strings.Join(names, " and ")
 }
@@ -0,0 +1,71 @@
+// Package builtins holds the functions implemented in Go that WordLang
+// programs import from built-in modules, e.g. 'from stdio import println'.
+package builtins
+
+import (
+	"sort"
+	"strings"
+	"wordlang/diagnostic"
+	"wordlang/object"
+)
+
+// registry maps a module name to the builtins it provides.
+var registry = map[string]map[string]*object.Builtin{}
+
+// Register adds a builtin to a module, creating the module on first use.
+// Library files call it from init, so new functions need no lexer or parser changes.
+func Register(module, name string, fn object.BuiltinFunction) {
+	fns, ok := registry[module]
+	if !ok {
+		fns = map[string]*object.Builtin{}
+		registry[module] = fns
+	}
+	fns[name] = &object.Builtin{Module: module, Name: name, Fn: fn}
+}
+
+// Module returns the builtins of a module, or false if no such module is registered.
+func Module(name string) (map[string]*object.Builtin, bool) {
+	fns, ok := registry[name]
+	return fns, ok
+}
+
+// Modules returns the names of all registered modules, sorted.
+func Modules() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckArgCount reports a call with the wrong number of arguments.
+func CheckArgCount(name string, args []object.Object, want int) *object.Error {
+	if len(args) != want {
+		return object.NewCodedError(diagnostic.CodeArgumentCount, "Eval: Wrong number of arguments for %s: expected %d, got %d", name, want, len(args))
+	}
+	return nil
+}
+
+// CheckArgType reports an argument whose type is not one of types.
+func CheckArgType(name string, args []object.Object, i int, types ...object.ObjectType) *object.Error {
+	for _, t := range types {
+		if args[i].Type() == t {
+			return nil
+		}
+	}
+	expected := make([]string, len(types))
+	for j, t := range types {
+		expected[j] = string(t)
+	}
+	return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Argument %d of %s must be %s, got %s", i+1, name, strings.Join(expected, " or "), args[i].Type())
+}
+
+// inspectAll renders values the way 'print' does, separated by spaces.
+func inspectAll(args []object.Object) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = arg.Inspect()
+	}
+	return strings.Join(parts, " ")
+}
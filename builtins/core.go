@@ -0,0 +1,37 @@
+package builtins
+
+import (
+	"strings"
+	"unicode/utf8"
+	"wordlang/object"
+)
+
+func init() {
+	Register("core", "length", coreLength)
+	Register("core", "typeof", coreTypeof)
+}
+
+// coreLength counts the characters of a string or the elements of a list.
+func coreLength(env *object.Environment, args ...object.Object) object.Object {
+	if err := CheckArgCount("length", args, 1); err != nil {
+		return err
+	}
+	if err := CheckArgType("length", args, 0, object.STRING_OBJ, object.LIST_OBJ); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *object.String:
+		return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+	default:
+		return &object.Integer{Value: int64(len(arg.(*object.List).Elements))}
+	}
+}
+
+// coreTypeof names the type of a value in lower case, e.g. "integer" or "list".
+func coreTypeof(env *object.Environment, args ...object.Object) object.Object {
+	if err := CheckArgCount("typeof", args, 1); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ToLower(string(args[0].Type()))}
+}
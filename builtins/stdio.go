@@ -0,0 +1,53 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"wordlang/object"
+)
+
+func init() {
+	Register("stdio", "print", stdioPrint)
+	Register("stdio", "println", stdioPrintln)
+	Register("stdio", "eprintln", stdioEprintln)
+	Register("stdio", "input", stdioInput)
+}
+
+// stdioPrint writes its arguments separated by spaces, without a newline.
+func stdioPrint(env *object.Environment, args ...object.Object) object.Object {
+	fmt.Fprint(env.Session().Stdout, inspectAll(args))
+	return object.NULL
+}
+
+// stdioPrintln writes its arguments separated by spaces, followed by a newline.
+func stdioPrintln(env *object.Environment, args ...object.Object) object.Object {
+	fmt.Fprintln(env.Session().Stdout, inspectAll(args))
+	return object.NULL
+}
+
+// stdioEprintln is println for the error stream.
+func stdioEprintln(env *object.Environment, args ...object.Object) object.Object {
+	fmt.Fprintln(env.Session().Stderr, inspectAll(args))
+	return object.NULL
+}
+
+// stdioInput shows an optional prompt and reads one line, without its line ending.
+func stdioInput(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return CheckArgCount("input", args, 1)
+	}
+	session := env.Session()
+	if len(args) == 1 {
+		if err := CheckArgType("input", args, 0, object.STRING_OBJ); err != nil {
+			return err
+		}
+		fmt.Fprint(session.Stdout, args[0].Inspect())
+	}
+
+	line, err := session.Stdin.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return object.NewError("Eval: input failed: %s", err)
+	}
+	return &object.String{Value: strings.TrimRight(line, "\r\n")}
+}
@@ -0,0 +1,41 @@
+package builtins
+
+import (
+	"strings"
+	"wordlang/object"
+)
+
+func init() {
+	Register("strings", "uppercase", stringsUppercase)
+	Register("strings", "lowercase", stringsLowercase)
+	Register("strings", "trim", stringsTrim)
+}
+
+func stringsUppercase(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkString("uppercase", args); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ToUpper(args[0].(*object.String).Value)}
+}
+
+func stringsLowercase(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkString("lowercase", args); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ToLower(args[0].(*object.String).Value)}
+}
+
+func stringsTrim(env *object.Environment, args ...object.Object) object.Object {
+	if err := checkString("trim", args); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.TrimSpace(args[0].(*object.String).Value)}
+}
+
+// checkString validates the single string argument the string builtins take.
+func checkString(name string, args []object.Object) *object.Error {
+	if err := CheckArgCount(name, args, 1); err != nil {
+		return err
+	}
+	return CheckArgType(name, args, 0, object.STRING_OBJ)
+}
@@ -0,0 +1,97 @@
+// Package diagnostic describes problems found in WordLang source, by the
+// lexer, the parser or the interpreter, and renders them for people and tools.
+package diagnostic
+
+import (
+	"fmt"
+	"unicode/utf8"
+	"wordlang/token"
+)
+
+// Severity tells how serious a diagnostic is.
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Diagnostic codes. L is for the lexer, P for the parser, R for runtime errors.
+const (
+	CodeIllegalCharacter    = "L0001"
+	CodeUnterminatedString  = "L0002"
+	CodeInvalidEscape       = "L0003"
+	CodeUnterminatedComment = "L0004"
+	CodeMalformedNumber     = "L0005"
+
+	CodeUnexpectedToken = "P0001"
+	CodeExpectedExpr    = "P0002"
+	CodeInvalidNumber   = "P0003"
+	CodeMisplaced       = "P0004" // A well-formed statement where it is not allowed
+	CodeUnexpectedEOF   = "P0005" // The input ended inside a statement or block
+
+	CodeRuntime         = "R0001"
+	CodeUndefinedName   = "R0002"
+	CodeTypeMismatch    = "R0003"
+	CodeDivisionByZero  = "R0004"
+	CodeIndexOutOfRange = "R0005"
+	CodeArgumentCount   = "R0006"
+	CodeImport          = "R0007"
+	CodeCancelled       = "R0008"
+	CodeRaised          = "R0009" // Raised by the program with 'raise'
+	CodeMissingKey      = "R0010"
+)
+
+// Position is a 1-based line and column; columns count characters.
+// The zero Position means the location is unknown.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Span is the source range a diagnostic refers to. End is exclusive.
+type Span struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Diagnostic is one problem report.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	File     string   `json:"file,omitempty"`
+	Span     Span     `json:"span"`
+	Message  string   `json:"message"`
+	Notes    []string `json:"notes,omitempty"`
+}
+
+// New creates an error diagnostic.
+func New(code string, span Span, format string, a ...interface{}) *Diagnostic {
+	return &Diagnostic{Severity: Error, Code: code, Span: span, Message: fmt.Sprintf(format, a...)}
+}
+
+// TokenSpan is the span covered by a token's literal.
+func TokenSpan(tok token.Token) Span {
+	start := Position{Line: tok.Line, Column: tok.Column}
+	end := start
+	end.Column += utf8.RuneCountInString(tok.Literal)
+	if end.Column == start.Column {
+		end.Column++ // EOF and friends still get a one character caret
+	}
+	return Span{Start: start, End: end}
+}
+
+// Error formats the diagnostic on one line: "file:line:col: error[P0001]: message".
+func (d *Diagnostic) Error() string {
+	location := d.File
+	if d.Span.Start.Line > 0 {
+		if location == "" {
+			location = "<input>" // Source that did not come from a file
+		}
+		location = fmt.Sprintf("%s:%d:%d", location, d.Span.Start.Line, d.Span.Start.Column)
+	}
+	if location != "" {
+		location += ": "
+	}
+	return fmt.Sprintf("%s%s[%s]: %s", location, d.Severity, d.Code, d.Message)
+}
@@ -0,0 +1,75 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Render writes a diagnostic for people: the message, its location, and,
+// when source is known, the offending line with a caret underline.
+//
+//	error[P0001]: expected next token to be BE, got NUMBER instead
+//	 --> test.wl:3:7
+//	  |
+//	3 | let x 5
+//	  |       ^
+func Render(w io.Writer, d *Diagnostic, source string) {
+	fmt.Fprintf(w, "%s[%s]: %s\n", d.Severity, d.Code, d.Message)
+
+	start := d.Span.Start
+	lines := strings.Split(source, "\n")
+	if start.Line < 1 || start.Line > len(lines) {
+		if d.File != "" {
+			fmt.Fprintf(w, " --> %s\n", d.File)
+		}
+		renderNotes(w, d, "")
+		return
+	}
+
+	file := d.File
+	if file == "" {
+		file = "<input>"
+	}
+	gutter := strings.Repeat(" ", len(strconv.Itoa(start.Line)))
+	fmt.Fprintf(w, "%s--> %s:%d:%d\n", gutter, file, start.Line, start.Column)
+	fmt.Fprintf(w, "%s |\n", gutter)
+
+	line := strings.TrimRight(lines[start.Line-1], "\r")
+	fmt.Fprintf(w, "%d | %s\n", start.Line, strings.ReplaceAll(line, "\t", " "))
+
+	width := utf8.RuneCountInString(line)
+	end := d.Span.End.Column
+	if d.Span.End.Line != start.Line || end > width+1 {
+		end = width + 1 // Underline to the end of the line
+	}
+	carets := end - start.Column
+	if carets < 1 {
+		carets = 1
+	}
+	padding := start.Column - 1
+	if padding < 0 {
+		padding = 0
+	}
+	fmt.Fprintf(w, "%s | %s%s\n", gutter, strings.Repeat(" ", padding), strings.Repeat("^", carets))
+	renderNotes(w, d, gutter)
+}
+
+func renderNotes(w io.Writer, d *Diagnostic, gutter string) {
+	for _, note := range d.Notes {
+		fmt.Fprintf(w, "%s = note: %s\n", gutter, note)
+	}
+}
+
+// RenderJSON writes diagnostics as a JSON array, for editors and CI annotations.
+func RenderJSON(w io.Writer, diagnostics []*Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []*Diagnostic{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diagnostics)
+}
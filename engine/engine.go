@@ -0,0 +1,253 @@
+// Package engine embeds the WordLang interpreter in Go programs.
+//
+// A Runtime owns a global environment and its own input and output streams,
+// so several runtimes can run in one process without sharing bindings or
+// output:
+//
+//	var out bytes.Buffer
+//	rt := engine.New(engine.Options{Stdout: &out})
+//	if _, err := rt.Run(ctx, `print "hello"`); err != nil {
+//		// *ParseError or *RuntimeError
+//	}
+//
+// Parsing is separate from execution: Parse checks a program once, and Exec
+// runs the result in a runtime.
+package engine
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"wordlang/ast"
+	"wordlang/diagnostic"
+	"wordlang/interpreter"
+	"wordlang/object"
+	"wordlang/parser"
+)
+
+// Options configures a Runtime. The zero value gives a runtime with no input
+// whose output is discarded.
+type Options struct {
+	Stdin  io.Reader // Read by 'input'; nil reads as empty
+	Stdout io.Writer // Written by 'print'; nil discards
+	Stderr io.Writer // Written by stdio.eprintln; nil discards
+
+	// File names the program in diagnostics. Its imports are resolved relative
+	// to it, or to the working directory if File is empty.
+	File string
+	// SearchPath lists directories searched for modules after the program's own.
+	SearchPath []string
+}
+
+// Runtime runs WordLang programs in one global environment. Bindings made by
+// one Run are visible to the next. A Runtime must not be used by several
+// goroutines at once.
+type Runtime struct {
+	file string
+	env  *object.Environment
+}
+
+// New creates a runtime with an empty global environment.
+func New(opts Options) *Runtime {
+	session := object.NewSession()
+	session.SearchPath = opts.SearchPath
+	session.Stdin = bufio.NewReader(orEmpty(opts.Stdin))
+	session.Stdout = orDiscard(opts.Stdout)
+	session.Stderr = orDiscard(opts.Stderr)
+
+	env := session.NewEnvironment("")
+	if opts.File != "" {
+		env = interpreter.NewFileEnvironment(session, opts.File)
+	}
+	return &Runtime{file: opts.File, env: env}
+}
+
+func orEmpty(r io.Reader) io.Reader {
+	if r == nil {
+		return strings.NewReader("")
+	}
+	return r
+}
+
+func orDiscard(w io.Writer) io.Writer {
+	if w == nil {
+		return io.Discard
+	}
+	return w
+}
+
+// Parse parses source without running it. file names the source in the
+// diagnostics, which hold the lexer and parser errors in source order.
+func Parse(file, source string) (*ast.Program, []*diagnostic.Diagnostic) {
+	return parser.Parse(file, source)
+}
+
+// Run parses and runs source, returning the value of its last statement. A
+// program that does not parse is not run and the error is a *ParseError.
+func (r *Runtime) Run(ctx context.Context, source string) (object.Object, error) {
+	program, diagnostics := Parse(r.file, source)
+	if len(diagnostics) != 0 {
+		return nil, &ParseError{Diagnostics: diagnostics}
+	}
+	return r.Exec(ctx, program)
+}
+
+// Exec runs a parsed program in the global environment and returns the value
+// of its last statement. Cancelling ctx stops the program at the next loop
+// iteration or function call with a *RuntimeError. A program that runs
+// 'exit' with a non-zero code returns an *ExitError.
+func (r *Runtime) Exec(ctx context.Context, program *ast.Program) (object.Object, error) {
+	return r.eval(ctx, func() object.Object {
+		return interpreter.Eval(program, r.env)
+	})
+}
+
+// Call calls the global function named name. Arguments are converted with ToObject.
+func (r *Runtime) Call(ctx context.Context, name string, args ...any) (object.Object, error) {
+	fn, ok := r.env.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("engine: no global named %q", name)
+	}
+
+	objs := make([]object.Object, len(args))
+	for i, arg := range args {
+		obj, err := ToObject(arg)
+		if err != nil {
+			return nil, fmt.Errorf("engine: argument %d of %s: %w", i+1, name, err)
+		}
+		objs[i] = obj
+	}
+
+	return r.eval(ctx, func() object.Object {
+		return interpreter.ApplyFunction(fn, objs, r.env)
+	})
+}
+
+// eval runs fn under ctx and turns a WordLang error or a non-zero exit into a
+// Go error. 'exit 0' ends the program successfully.
+func (r *Runtime) eval(ctx context.Context, fn func() object.Object) (object.Object, error) {
+	session := r.env.Session()
+	session.Context = ctx
+	defer func() { session.Context = context.Background() }()
+
+	result := fn()
+	switch result := result.(type) {
+	case *object.Error:
+		return nil, &RuntimeError{Err: result, File: r.file}
+	case *object.Exit:
+		if result.Code != 0 {
+			return nil, &ExitError{Code: result.Code}
+		}
+		return object.NULL, nil
+	}
+	if result == nil {
+		return object.NULL, nil
+	}
+	return result, nil
+}
+
+// SetGlobal binds name in the global environment. The value is converted with ToObject.
+func (r *Runtime) SetGlobal(name string, value any) error {
+	obj, err := ToObject(value)
+	if err != nil {
+		return fmt.Errorf("engine: global %s: %w", name, err)
+	}
+	r.env.Set(name, obj)
+	return nil
+}
+
+// Get returns the value bound to a global name.
+func (r *Runtime) Get(name string) (object.Object, bool) {
+	return r.env.Get(name)
+}
+
+// ToObject converts a Go value to a WordLang value. It accepts nil, booleans,
+// integers, floats, strings and slices of those; object.Object values are
+// returned unchanged.
+func ToObject(value any) (object.Object, error) {
+	switch v := value.(type) {
+	case object.Object:
+		return v, nil
+	case nil:
+		return object.NULL, nil
+	case bool:
+		if v {
+			return object.TRUE, nil
+		}
+		return object.FALSE, nil
+	case int:
+		return &object.Integer{Value: int64(v)}, nil
+	case int32:
+		return &object.Integer{Value: int64(v)}, nil
+	case int64:
+		return &object.Integer{Value: v}, nil
+	case float32:
+		return &object.Float{Value: float64(v)}, nil
+	case float64:
+		return &object.Float{Value: v}, nil
+	case string:
+		return &object.String{Value: v}, nil
+	case []any:
+		list := &object.List{Elements: make([]object.Object, len(v))}
+		for i, elem := range v {
+			obj, err := ToObject(elem)
+			if err != nil {
+				return nil, err
+			}
+			list.Elements[i] = obj
+		}
+		return list, nil
+	case []string:
+		list := &object.List{Elements: make([]object.Object, len(v))}
+		for i, elem := range v {
+			list.Elements[i] = &object.String{Value: elem}
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to a WordLang value", value)
+}
+
+// ParseError reports a program that could not be parsed.
+type ParseError struct {
+	Diagnostics []*diagnostic.Diagnostic // In source order
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Diagnostics) == 1 {
+		return e.Diagnostics[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e.Diagnostics[0].Error(), len(e.Diagnostics)-1)
+}
+
+// ExitError reports a program that ended with a non-zero 'exit'. The runtime
+// stays usable; it is up to the host what the code means.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// RuntimeError reports an error raised while a program ran.
+type RuntimeError struct {
+	Err  *object.Error
+	File string // The program the runtime was created for
+}
+
+func (e *RuntimeError) Error() string {
+	return e.Diagnostic().Error()
+}
+
+// Diagnostic converts the error into a diagnostic, ready to render.
+func (e *RuntimeError) Diagnostic() *diagnostic.Diagnostic {
+	return e.Err.Diagnostic(e.File)
+}
+
+// Diagnostics is Diagnostic followed by the diagnostics behind the error, such
+// as the syntax errors of a module that could not be imported.
+func (e *RuntimeError) Diagnostics() []*diagnostic.Diagnostic {
+	return e.Err.Diagnostics(e.File)
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wordlang/diagnostic"
+	"wordlang/object"
+)
+
+func TestRuntimesDoNotShareOutputOrBindings(t *testing.T) {
+	var outA, outB bytes.Buffer
+	a := New(Options{Stdout: &outA})
+	b := New(Options{Stdout: &outB})
+
+	if _, err := a.Run(context.Background(), "let x be 1\nprint \"a\""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Run(context.Background(), "print \"b\""); err != nil {
+		t.Fatal(err)
+	}
+
+	if outA.String() != "a\n" || outB.String() != "b\n" {
+		t.Errorf("got outputs %q and %q, want %q and %q", outA.String(), outB.String(), "a\n", "b\n")
+	}
+	if _, ok := b.Get("x"); ok {
+		t.Errorf("a binding made in one runtime is visible in another")
+	}
+}
+
+func TestRunKeepsBindings(t *testing.T) {
+	rt := New(Options{})
+	if _, err := rt.Run(context.Background(), "let x be 2"); err != nil {
+		t.Fatal(err)
+	}
+	result, err := rt.Run(context.Background(), "mult x by 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := result.(*object.Integer); !ok || n.Value != 6 {
+		t.Errorf("got %v, want 6", result)
+	}
+}
+
+func TestCallSetGlobalAndGet(t *testing.T) {
+	rt := New(Options{})
+	if _, err := rt.Run(context.Background(), "function scale n\nreturn mult n by factor\nend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.SetGlobal("factor", 10); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := rt.Call(context.Background(), "scale", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := result.(*object.Integer); !ok || n.Value != 40 {
+		t.Errorf("got %v, want 40", result)
+	}
+
+	factor, ok := rt.Get("factor")
+	if n, isInt := factor.(*object.Integer); !ok || !isInt || n.Value != 10 {
+		t.Errorf("got %v, want 10", factor)
+	}
+	if _, err := rt.Call(context.Background(), "missing"); err == nil {
+		t.Errorf("calling a missing function did not fail")
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	rt := New(Options{})
+
+	_, err := rt.Run(context.Background(), "let x 5")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || len(parseErr.Diagnostics) != 1 {
+		t.Errorf("got %v, want a *ParseError with one diagnostic", err)
+	}
+
+	_, err = rt.Run(context.Background(), "print div 1 by 0")
+	var runtimeErr *RuntimeError
+	if !errors.As(err, &runtimeErr) || runtimeErr.Err.Code != diagnostic.CodeDivisionByZero {
+		t.Errorf("got %v, want a *RuntimeError for division by zero", err)
+	}
+
+	_, err = rt.Run(context.Background(), "exit 3")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Errorf("got %v, want an *ExitError with code 3", err)
+	}
+
+	if _, err := rt.Run(context.Background(), "exit 0"); err != nil {
+		t.Errorf("'exit 0' returned %v, want no error", err)
+	}
+}
+
+func TestCancelStopsLoop(t *testing.T) {
+	rt := New(Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rt.Run(ctx, "while true do\nendwhile")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		var runtimeErr *RuntimeError
+		if !errors.As(err, &runtimeErr) || runtimeErr.Err.Code != diagnostic.CodeCancelled {
+			t.Errorf("got %v, want a cancelled *RuntimeError", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the loop did not stop after the context was cancelled")
+	}
+}
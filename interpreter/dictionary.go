@@ -0,0 +1,137 @@
+package interpreter
+
+import (
+	"wordlang/ast"
+	"wordlang/diagnostic"
+	"wordlang/object"
+)
+
+func evalDictionaryLiteral(dl *ast.DictionaryLiteral, env *Environment) object.Object {
+	dict := object.NewMap()
+
+	for i, keyNode := range dl.Keys {
+		key, err := evalDictionaryKey(keyNode, env)
+		if err != nil {
+			return err
+		}
+		value := Eval(dl.Values[i], env)
+		if isUnwinding(value) {
+			return value
+		}
+		dict.Set(key, value) // A repeated key keeps the last value
+	}
+
+	return dict
+}
+
+func evalValueForExpression(vfe *ast.ValueForExpression, env *Environment) object.Object {
+	key, err := evalDictionaryKey(vfe.Key, env)
+	if err != nil {
+		return err
+	}
+	dict, err := evalDictionary(vfe.Dictionary, env, "value for")
+	if err != nil {
+		return err
+	}
+
+	value, ok := dict.Get(key)
+	if !ok {
+		return object.NewCodedError(diagnostic.CodeMissingKey, "Eval: the dictionary has no key %s", describeKey(key))
+	}
+	return value
+}
+
+func evalSetValueStatement(svs *ast.SetValueStatement, env *Environment) object.Object {
+	key, err := evalDictionaryKey(svs.Key, env)
+	if err != nil {
+		return err
+	}
+	dict, err := evalDictionary(svs.Dictionary, env, "set value for")
+	if err != nil {
+		return err
+	}
+	value := Eval(svs.Value, env)
+	if isUnwinding(value) {
+		return value
+	}
+
+	dict.Set(key, value)
+	return value
+}
+
+// evalRemoveStatement removes a key from a dictionary, or an item from a list.
+func evalRemoveStatement(rs *ast.RemoveStatement, env *Environment) object.Object {
+	from := Eval(rs.From, env)
+	if isUnwinding(from) {
+		return from
+	}
+	if list, ok := from.(*object.List); ok {
+		return removeFromList(rs, list, env)
+	}
+	dict, ok := from.(*object.Map)
+	if !ok {
+		return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: 'remove' expected a list or a dictionary, got %s", from.Type())
+	}
+	if rs.Index != nil {
+		return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: 'remove item at index' expected a list, got %s", from.Type())
+	}
+
+	key, err := evalDictionaryKey(rs.Value, env)
+	if err != nil {
+		return err
+	}
+	if !dict.Delete(key) {
+		return object.NewCodedError(diagnostic.CodeMissingKey, "Eval: cannot remove %s, the dictionary has no such key", describeKey(key))
+	}
+	return object.NULL
+}
+
+func evalHasKeyInfixExpression(operator string, left, right object.Object) object.Object {
+	dict, ok := left.(*object.Map)
+	if !ok {
+		return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: '%s' expected a dictionary on the left, got %s", operator, left.Type())
+	}
+	key, ok := right.(object.Hashable)
+	if !ok {
+		return nativeBoolToBooleanObject(false) // Such a value is never a key
+	}
+	_, found := dict.Get(key)
+	return nativeBoolToBooleanObject(found)
+}
+
+// evalDictionaryKey evaluates an expression used as a dictionary key. Only
+// strings, integers and booleans can be keys.
+func evalDictionaryKey(node ast.Expression, env *Environment) (object.Hashable, object.Object) {
+	key := Eval(node, env)
+	if isUnwinding(key) {
+		return nil, key
+	}
+	hashable, ok := key.(object.Hashable)
+	if !ok {
+		return nil, object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: a dictionary key must be a string, a number or a boolean, got %s", key.Type())
+	}
+	return hashable, nil
+}
+
+// evalDictionary evaluates an expression that must give a dictionary. op
+// names the operation in errors.
+func evalDictionary(node ast.Expression, env *Environment, op string) (*object.Map, object.Object) {
+	obj := Eval(node, env)
+	if isUnwinding(obj) {
+		return nil, obj
+	}
+	dict, ok := obj.(*object.Map)
+	if !ok {
+		return nil, object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: '%s' expected a dictionary, got %s", op, obj.Type())
+	}
+	return dict, nil
+}
+
+// describeKey shows a key in an error message, quoting strings so that "1"
+// and 1 can be told apart.
+func describeKey(key object.Hashable) string {
+	if s, ok := key.(*object.String); ok {
+		return `"` + s.Value + `"`
+	}
+	return key.Inspect()
+}
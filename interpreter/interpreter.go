@@ -2,43 +2,44 @@ package interpreter
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"math"
 	"strconv"
 	"strings"
 	"wordlang/ast"
+	"wordlang/diagnostic"
 	"wordlang/object"
+	"wordlang/token"
 )
 
-// Environment holds variable bindings.
-type Environment struct {
-	store map[string]object.Object
-	outer *Environment // For scopes (not implemented yet in this basic version)
-}
+// Environment holds variable bindings. It lives in the object package so that
+// functions can capture the environment they were defined in.
+type Environment = object.Environment
 
 // NewEnvironment creates a new environment.
 func NewEnvironment() *Environment {
-	s := make(map[string]object.Object)
-	return &Environment{store: s, outer: nil}
+	return object.NewEnvironment()
 }
 
-// Get retrieves a variable from the environment.
-func (e *Environment) Get(name string) (object.Object, bool) {
-	obj, ok := e.store[name]
-	if !ok && e.outer != nil { // Scope lookup (not fully implemented yet)
-		obj, ok = e.outer.Get(name)
-	}
-	return obj, ok
+// NewEnclosedEnvironment creates a new environment enclosed by outer environment.
+func NewEnclosedEnvironment(outer *Environment) *Environment {
+	return object.NewEnclosedEnvironment(outer)
 }
 
-// Set sets a variable in the environment.
-func (e *Environment) Set(name string, val object.Object) object.Object {
-	e.store[name] = val
-	return val
+// Eval evaluates an AST node. A runtime error gets the position of the
+// innermost node it came out of.
+func Eval(node ast.Node, env *Environment) object.Object {
+	result := eval(node, env)
+	if err, ok := result.(*object.Error); ok && err.Span.Start.Line == 0 && node != nil {
+		if tok := node.Pos(); tok.Line > 0 {
+			err.Span = diagnostic.TokenSpan(tok)
+			err.File = env.File()
+		}
+	}
+	return result
 }
 
-
-// Eval evaluates an AST node.
-func Eval(node ast.Node, env *Environment) object.Object {
+func eval(node ast.Node, env *Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
@@ -50,6 +51,8 @@ func Eval(node ast.Node, env *Environment) object.Object {
 		return evalFloatLiteral(node)
 	case *ast.StringLiteral:
 		return evalStringLiteral(node)
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
 	case *ast.BooleanLiteral:
 		return evalBooleanLiteral(node)
 	case *ast.PrefixExpression:
@@ -64,6 +67,34 @@ func Eval(node ast.Node, env *Environment) object.Object {
 		return evalWhileStatement(node, env)
 	case *ast.ForEachStatement:
 		return evalForEachStatement(node, env)
+	case *ast.RepeatStatement:
+		return evalRepeatStatement(node, env)
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
+	case *ast.IncrementStatement:
+		return evalIncrementStatement(node, env)
+	case *ast.SetStatement:
+		return evalSetStatement(node, env)
+	case *ast.WhenStatement:
+		return evalWhenStatement(node, env)
+	case *ast.TryStatement:
+		return evalTryStatement(node, env)
+	case *ast.RaiseStatement:
+		return evalRaiseStatement(node, env)
+	case *ast.DictionaryLiteral:
+		return evalDictionaryLiteral(node, env)
+	case *ast.ValueForExpression:
+		return evalValueForExpression(node, env)
+	case *ast.SetValueStatement:
+		return evalSetValueStatement(node, env)
+	case *ast.RemoveStatement:
+		return evalRemoveStatement(node, env)
+	case *ast.AppendStatement:
+		return evalAppendStatement(node, env)
+	case *ast.InsertStatement:
+		return evalInsertStatement(node, env)
+	case *ast.SetItemStatement:
+		return evalSetItemStatement(node, env)
 	case *ast.LetStatement:
 		return evalLetStatement(node, env)
 	case *ast.ReturnStatement:
@@ -82,10 +113,24 @@ func Eval(node ast.Node, env *Environment) object.Object {
 		return evalIsDefinedExpression(node, env)
 	case *ast.ExitStatement:
 		return evalExitStatement(node, env)
+	case *ast.StopStatement:
+		return &object.Break{Label: labelName(node.Label)}
+	case *ast.SkipStatement:
+		return &object.Continue{Label: labelName(node.Label)}
 	case *ast.ConvertToNumberExpression:
 		return evalConvertToNumberExpression(node, env)
 	case *ast.ConvertToStringExpression:
 		return evalConvertToStringExpression(node, env)
+	case *ast.FunctionLiteral:
+		return &object.Function{Parameters: node.Parameters, Body: node.Body, Env: env}
+	case *ast.FunctionStatement:
+		return evalFunctionStatement(node, env)
+	case *ast.CallExpression:
+		return evalCallExpression(node, env)
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+	case *ast.BadStatement, *ast.BadExpression:
+		return object.NewError("Eval: Cannot run code with syntax errors: %s", node.String())
 	default:
 		return object.NewError("Eval: Node type not handled: %T", node)
 	}
@@ -101,8 +146,8 @@ func evalProgram(program *ast.Program, env *Environment) object.Object {
 			return returnValue.Value
 		}
 
-		if errObj, ok := result.(*object.Error); ok {
-			return errObj // Propagate errors
+		if isUnwinding(result) {
+			return result // Propagate errors and exit
 		}
 	}
 
@@ -117,7 +162,8 @@ func evalBlockStatement(block *ast.BlockStatement, env *Environment) object.Obje
 
 		if result != nil {
 			rt := result.Type()
-			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ || rt == object.EXIT_OBJ ||
+				rt == object.BREAK_OBJ || rt == object.CONTINUE_OBJ {
 				return result
 			}
 		}
@@ -138,6 +184,20 @@ func evalStringLiteral(sl *ast.StringLiteral) object.Object {
 	return &object.String{Value: sl.Value}
 }
 
+// evalInterpolatedString evaluates each embedded expression in order and joins
+// the results with the surrounding text. Values are inserted as print shows them.
+func evalInterpolatedString(ip *ast.InterpolatedString, env *Environment) object.Object {
+	var out strings.Builder
+	for _, part := range ip.Parts {
+		val := Eval(part, env)
+		if isUnwinding(val) {
+			return val
+		}
+		out.WriteString(val.Inspect())
+	}
+	return &object.String{Value: out.String()}
+}
+
 func evalBooleanLiteral(bl *ast.BooleanLiteral) object.Object {
 	return nativeBoolToBooleanObject(bl.Value)
 }
@@ -151,13 +211,15 @@ func nativeBoolToBooleanObject(input bool) object.Object {
 
 func evalPrefixExpression(pe *ast.PrefixExpression, env *Environment) object.Object {
 	right := Eval(pe.Right, env)
-	if isError(right) {
+	if isUnwinding(right) {
 		return right
 	}
 
 	switch pe.Operator {
 	case "not":
 		return evalNotOperatorExpression(right)
+	case "-":
+		return evalMinusPrefixOperatorExpression(right)
 	default:
 		return object.NewError("Eval: Unknown prefix operator: %s%s", pe.Operator, right.Type())
 	}
@@ -176,33 +238,59 @@ func evalNotOperatorExpression(right object.Object) object.Object {
 	}
 }
 
+func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: -right.Value}
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
+		return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Cannot negate type %s", right.Type())
+	}
+}
+
 func evalInfixExpression(ie *ast.InfixExpression, env *Environment) object.Object {
 	left := Eval(ie.Left, env)
-	if isError(left) {
+	if isUnwinding(left) {
 		return left
 	}
 
+	switch ie.Operator { // 'and' and 'or' only evaluate the right side when it can change the result
+	case "and":
+		if !isTruthy(left) {
+			return object.FALSE
+		}
+	case "or":
+		if isTruthy(left) {
+			return object.TRUE
+		}
+	}
+
 	right := Eval(ie.Right, env)
-	if isError(right) {
+	if isUnwinding(right) {
 		return right
 	}
 
+	if ie.InPlace {
+		return evalInPlaceInfixExpression(ie, left, right, env)
+	}
+
 	switch ie.Operator {
 	case "add":
 		return evalAddInfixExpression(ie.Operator, left, right)
-	case "subtract":
+	case "sub":
 		return evalSubtractInfixExpression(ie.Operator, left, right)
-	case "multiply":
+	case "mult":
 		return evalMultiplyInfixExpression(ie.Operator, left, right)
-	case "divide":
+	case "div":
 		return evalDivideInfixExpression(ie.Operator, left, right)
 	case "equals":
 		return evalEqualsInfixExpression(ie.Operator, left, right)
 	case "notequals":
 		return evalNotEqualsInfixExpression(ie.Operator, left, right)
-	case "greater":
+	case "greater", "greater than":
 		return evalGreaterThanInfixExpression(ie.Operator, left, right)
-	case "less":
+	case "less", "less than":
 		return evalLessThanInfixExpression(ie.Operator, left, right)
 	case "greater or equal":
 		return evalGreaterOrEqualInfixExpression(ie.Operator, left, right)
@@ -212,11 +300,35 @@ func evalInfixExpression(ie *ast.InfixExpression, env *Environment) object.Objec
 		return evalAndInfixExpression(ie.Operator, left, right)
 	case "or":
 		return evalOrInfixExpression(ie.Operator, left, right)
+	case "has key":
+		return evalHasKeyInfixExpression(ie.Operator, left, right)
 	default:
 		return object.NewError("Eval: Unknown infix operator: %s %s %s", left.Type(), ie.Operator, right.Type())
 	}
 }
 
+// evalInPlaceInfixExpression handles 'add 5 to x': the total is stored back into x and returned.
+func evalInPlaceInfixExpression(ie *ast.InfixExpression, left, right object.Object, env *Environment) object.Object {
+	target, ok := ie.Left.(*ast.Identifier)
+	if !ok {
+		return object.NewError("Eval: '%s ... to' needs a variable to update, got %s", ie.Operator, ie.Left.String())
+	}
+
+	var result object.Object
+	switch ie.Operator {
+	case "add":
+		result = evalAddInfixExpression(ie.Operator, left, right)
+	default:
+		return object.NewError("Eval: Operator '%s' has no in-place form", ie.Operator)
+	}
+	if isUnwinding(result) {
+		return result
+	}
+
+	env.Assign(target.Value, result) // The target was found when it was evaluated, so it is bound
+	return result
+}
+
 func evalAddInfixExpression(operator string, left, right object.Object) object.Object {
 	if left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ {
 		leftVal := left.(*object.Integer).Value
@@ -243,7 +355,7 @@ func evalAddInfixExpression(operator string, left, right object.Object) object.O
 		rightVal := right.(*object.String).Value
 		return &object.String{Value: leftVal + rightVal} // String concatenation
 	}
-	return object.NewError("Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
+	return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
 }
 
 func evalSubtractInfixExpression(operator string, left, right object.Object) object.Object {
@@ -267,7 +379,7 @@ func evalSubtractInfixExpression(operator string, left, right object.Object) obj
 		rightVal := right.(*object.Float).Value
 		return &object.Float{Value: leftVal - rightVal}
 	}
-	return object.NewError("Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
+	return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
 }
 
 func evalMultiplyInfixExpression(operator string, left, right object.Object) object.Object {
@@ -291,12 +403,19 @@ func evalMultiplyInfixExpression(operator string, left, right object.Object) obj
 		rightVal := right.(*object.Float).Value
 		return &object.Float{Value: leftVal * rightVal}
 	}
-	return object.NewError("Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
+	return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
 }
 
 func evalDivideInfixExpression(operator string, left, right object.Object) object.Object {
-	if right.(*object.Integer).Value == 0 && right.Type() == object.INTEGER_OBJ || right.(*object.Float).Value == 0 && right.Type() == object.FLOAT_OBJ{
-		return object.NewError("Eval: Division by zero error")
+	switch divisor := right.(type) {
+	case *object.Integer:
+		if divisor.Value == 0 {
+			return object.NewCodedError(diagnostic.CodeDivisionByZero, "Eval: Division by zero error")
+		}
+	case *object.Float:
+		if divisor.Value == 0 {
+			return object.NewCodedError(diagnostic.CodeDivisionByZero, "Eval: Division by zero error")
+		}
 	}
 	if left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ {
 		leftVal := left.(*object.Integer).Value
@@ -318,7 +437,7 @@ func evalDivideInfixExpression(operator string, left, right object.Object) objec
 		rightVal := right.(*object.Float).Value
 		return &object.Float{Value: leftVal / rightVal}
 	}
-	return object.NewError("Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
+	return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
 }
 
 
@@ -358,7 +477,7 @@ func evalEqualsInfixExpression(operator string, left, right object.Object) objec
 
 func evalNotEqualsInfixExpression(operator string, left, right object.Object) object.Object {
 	equalsResult := evalEqualsInfixExpression("equals", left, right) // Reuse equals logic
-	if isError(equalsResult) {
+	if isUnwinding(equalsResult) {
 		return equalsResult
 	}
 	return evalNotOperatorExpression(equalsResult) // Invert the result of equals
@@ -385,7 +504,7 @@ func evalGreaterThanInfixExpression(operator string, left, right object.Object)
 		rightVal := right.(*object.Float).Value
 		return nativeBoolToBooleanObject(leftVal > rightVal)
 	}
-	return object.NewError("Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
+	return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
 }
 
 func evalLessThanInfixExpression(operator string, left, right object.Object) object.Object {
@@ -409,7 +528,7 @@ func evalLessThanInfixExpression(operator string, left, right object.Object) obj
 		rightVal := right.(*object.Float).Value
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	}
-	return object.NewError("Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
+	return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
 }
 
 func evalGreaterOrEqualInfixExpression(operator string, left, right object.Object) object.Object {
@@ -433,7 +552,7 @@ func evalGreaterOrEqualInfixExpression(operator string, left, right object.Objec
 		rightVal := right.(*object.Float).Value
 		return nativeBoolToBooleanObject(leftVal >= rightVal)
 	}
-	return object.NewError("Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
+	return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
 }
 
 func evalLessOrEqualInfixExpression(operator string, left, right object.Object) object.Object {
@@ -457,7 +576,7 @@ func evalLessOrEqualInfixExpression(operator string, left, right object.Object)
 		rightVal := right.(*object.Float).Value
 		return nativeBoolToBooleanObject(leftVal <= rightVal)
 	}
-	return object.NewError("Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
+	return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Type mismatch for '%s' operator: %s %s %s", operator, left.Type(), operator, right.Type())
 }
 
 func evalAndInfixExpression(operator string, left, right object.Object) object.Object {
@@ -487,7 +606,7 @@ func isTruthy(obj object.Object) bool {
 
 func evalIfStatement(is *ast.IfStatement, env *Environment) object.Object {
 	condition := Eval(is.Condition, env)
-	if isError(condition) {
+	if isUnwinding(condition) {
 		return condition
 	}
 
@@ -496,7 +615,7 @@ func evalIfStatement(is *ast.IfStatement, env *Environment) object.Object {
 	} else {
 		for _, elseifBlock := range is.ElseIfBlocks {
 			elseifCondition := Eval(elseifBlock.Condition, env)
-			if isError(elseifCondition) {
+			if isUnwinding(elseifCondition) {
 				return elseifCondition
 			}
 			if isTruthy(elseifCondition) {
@@ -511,127 +630,553 @@ func evalIfStatement(is *ast.IfStatement, env *Environment) object.Object {
 	return object.NULL // No 'else' or condition not met, returns null
 }
 
-func evalWhileStatement(ws *ast.WhileStatement, env *Environment) object.Object {
-	var result object.Object = object.NULL // Default return value
+// evalWhenStatement evaluates the subject once and runs the first arm that
+// matches it, or the 'otherwise' block if none does.
+func evalWhenStatement(ws *ast.WhenStatement, env *Environment) object.Object {
+	subject := Eval(ws.Subject, env)
+	if isUnwinding(subject) {
+		return subject
+	}
+
+	for _, arm := range ws.Arms {
+		matched, err := whenArmMatches(arm, subject, env)
+		if err != nil {
+			return err
+		}
+		if matched {
+			return Eval(arm.Body, env)
+		}
+	}
 
+	if ws.Otherwise != nil {
+		return Eval(ws.Otherwise, env)
+	}
+	return object.NULL
+}
+
+// whenArmMatches reports whether subject equals one of the arm's values or,
+// for a range arm, is a number between its ends, both included. Values are
+// evaluated in order and only until one matches.
+func whenArmMatches(arm *ast.WhenArm, subject object.Object, env *Environment) (bool, object.Object) {
+	if arm.Low != nil {
+		low := Eval(arm.Low, env)
+		if isUnwinding(low) {
+			return false, low
+		}
+		high := Eval(arm.High, env)
+		if isUnwinding(high) {
+			return false, high
+		}
+		if !isNumber(low) || !isNumber(high) {
+			return false, object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: 'is between' needs two numbers, got %s and %s", low.Type(), high.Type())
+		}
+		if !isNumber(subject) {
+			return false, nil // Only numbers fall in a range
+		}
+		value := toFloat(subject)
+		return toFloat(low) <= value && value <= toFloat(high), nil
+	}
+
+	for _, expr := range arm.Values {
+		value := Eval(expr, env)
+		if isUnwinding(value) {
+			return false, value
+		}
+		if isTruthy(evalEqualsInfixExpression("equals", subject, value)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// evalTryStatement runs the body and, if it fails with a runtime error, the
+// handler. Only errors are caught: return, exit and loop signals pass through,
+// and so does cancellation, which a script must not be able to ignore. The
+// 'always' block runs last in every case; if it unwinds itself, that wins.
+func evalTryStatement(ts *ast.TryStatement, env *Environment) object.Object {
+	result := Eval(ts.Body, env)
+
+	if err, ok := result.(*object.Error); ok && ts.Handler != nil && err.Code != diagnostic.CodeCancelled {
+		handlerEnv := NewEnclosedEnvironment(env) // The error name is only visible in the handler
+		if ts.ErrorName != nil {
+			handlerEnv.Set(ts.ErrorName.Value, &object.String{Value: err.Text()})
+		}
+		result = Eval(ts.Handler, handlerEnv)
+	}
+
+	if ts.Always != nil {
+		cleanup := Eval(ts.Always, env)
+		if isUnwinding(cleanup) || isSignal(cleanup) {
+			return cleanup
+		}
+	}
+
+	return result
+}
+
+// isSignal reports whether obj is a return value or a loop signal, which
+// unwind to a function or a loop rather than out of the program.
+func isSignal(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.ReturnValue, *object.Break, *object.Continue:
+		return true
+	}
+	return false
+}
+
+// evalRaiseStatement fails with the given message. A value that is not a
+// string is shown as print would show it.
+func evalRaiseStatement(rs *ast.RaiseStatement, env *Environment) object.Object {
+	message := Eval(rs.Message, env)
+	if isUnwinding(message) {
+		return message
+	}
+	return object.NewCodedError(diagnostic.CodeRaised, "%s", message.Inspect())
+}
+
+func evalWhileStatement(ws *ast.WhileStatement, env *Environment) object.Object {
 	for {
 		condition := Eval(ws.Condition, env)
-		if isError(condition) {
+		if isUnwinding(condition) {
 			return condition
 		}
 		if !isTruthy(condition) {
 			break // Exit loop if condition is false
 		}
 
-		blockResult := Eval(ws.Body, env) // Execute loop body
-		if blockResult != nil && blockResult.Type() == object.RETURN_VALUE_OBJ {
-			return blockResult // Handle return statements inside loops
+		if done, result := runIteration(ws.Body, ws.Label, env); done {
+			return result
+		}
+	}
+
+	return object.NULL // A loop has no value of its own; a finished 'skip' must not leak out
+}
+
+// runIteration runs one pass of a loop body in env, first checking that the
+// program has not been cancelled. It reports whether the loop is done, and if
+// so what the loop returns: NULL after a 'stop' aimed at it, or whatever
+// loopControl passes on.
+func runIteration(body *ast.BlockStatement, label *ast.Identifier, env *Environment) (done bool, result object.Object) {
+	if err := checkCancelled(env); err != nil {
+		return true, err
+	}
+	leave, pass := loopControl(Eval(body, env), label)
+	if pass != nil {
+		return true, pass
+	}
+	if leave {
+		return true, object.NULL
+	}
+	return false, nil
+}
+
+// loopControl decides what a loop does after its body produced result. A
+// 'stop' aimed at this loop leaves it and a 'skip' aimed at it continues it.
+// Return values, errors, exit signals and stop/skip for an outer loop are
+// handed back in pass, for the loop to return.
+func loopControl(result object.Object, label *ast.Identifier) (leave bool, pass object.Object) {
+	switch signal := result.(type) {
+	case *object.Break:
+		if signal.Label == "" || signal.Label == labelName(label) {
+			return true, nil
 		}
-		if isError(blockResult) {
-			return blockResult // Propagate errors
+		return false, signal
+	case *object.Continue:
+		if signal.Label == "" || signal.Label == labelName(label) {
+			return false, nil
 		}
-		result = blockResult // Keep track of last evaluated value in the block (though might not be needed for 'while')
+		return false, signal
+	case *object.ReturnValue:
+		return false, signal
+	}
+	if isUnwinding(result) {
+		return false, result
 	}
+	return false, nil
+}
 
-	return result
+// labelName returns the name of an optional loop label, "" if there is none.
+func labelName(label *ast.Identifier) string {
+	if label == nil {
+		return ""
+	}
+	return label.Value
 }
 
 func evalForEachStatement(fes *ast.ForEachStatement, env *Environment) object.Object {
 	iterable := Eval(fes.Iterable, env)
-	if isError(iterable) {
+	if isUnwinding(iterable) {
 		return iterable
 	}
 
-	listObj, ok := iterable.(*object.List)
-	if !ok {
-		return object.NewError("Eval: 'for each' loop requires a list as iterable, got %s", iterable.Type())
+	var elements []object.Object
+	switch iterable := iterable.(type) {
+	case *object.List:
+		elements = append([]object.Object(nil), iterable.Elements...) // A copy, so the body may append and remove items
+	case *object.Map:
+		elements = iterable.Keys() // A copy, so the body may add and remove keys
+	default:
+		return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: 'for each' loop requires a list or a dictionary as iterable, got %s", iterable.Type())
 	}
 
-	var result object.Object = object.NULL // Default return value
-
-	for _, element := range listObj.Elements {
+	for _, element := range elements {
 		currentEnv := NewEnclosedEnvironment(env) // Create new scope for each iteration
 		currentEnv.Set(fes.Variable.Value, element)    // Bind loop variable
-		blockResult := Eval(fes.Body, currentEnv)        // Execute loop body in new scope
+		if done, result := runIteration(fes.Body, fes.Label, currentEnv); done {
+			return result
+		}
+	}
+
+	return object.NULL
+}
+
+func evalRepeatStatement(rs *ast.RepeatStatement, env *Environment) object.Object {
+	count := Eval(rs.Count, env)
+	if isUnwinding(count) {
+		return count
+	}
+
+	n, ok := count.(*object.Integer)
+	if !ok {
+		return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: 'repeat' needs a whole number of times, got %s", count.Type())
+	}
+	if n.Value < 0 {
+		return object.NewError("Eval: cannot repeat a negative number of times (%d)", n.Value)
+	}
+
+	for i := int64(0); i < n.Value; i++ {
+		// Fresh scope for each iteration, as in 'for each'
+		if done, result := runIteration(rs.Body, rs.Label, NewEnclosedEnvironment(env)); done {
+			return result
+		}
+	}
+
+	return object.NULL
+}
 
-		if blockResult != nil && blockResult.Type() == object.RETURN_VALUE_OBJ {
-			return blockResult // Handle return statements inside loops
+func evalForStatement(fs *ast.ForStatement, env *Environment) object.Object {
+	bounds := []ast.Expression{fs.Start, fs.End}
+	if fs.Step != nil {
+		bounds = append(bounds, fs.Step)
+	}
+	values := make([]object.Object, len(bounds))
+	for i, expr := range bounds {
+		values[i] = Eval(expr, env)
+		if isUnwinding(values[i]) {
+			return values[i]
 		}
-		if isError(blockResult) {
-			return blockResult // Propagate errors
+		if !isNumber(values[i]) {
+			return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: 'for' loop bounds must be numbers, got %s", values[i].Type())
 		}
-		result = blockResult // Keep track of last evaluated value in the block
 	}
 
-	return result
+	// The loop counts in integers when every bound is an integer, so the
+	// variable stays an integer; otherwise it counts in floats.
+	integers := true
+	for _, v := range values {
+		if _, ok := v.(*object.Integer); !ok {
+			integers = false
+		}
+	}
+
+	start, end := toFloat(values[0]), toFloat(values[1])
+	step := 1.0
+	if end < start {
+		step = -1 // A descending range counts down by default
+	}
+	if fs.Step != nil {
+		step = toFloat(values[2])
+	}
+	if step == 0 {
+		return object.NewError("Eval: 'for' loop step cannot be 0")
+	}
+	if (step > 0 && end < start) || (step < 0 && end > start) {
+		return object.NewError("Eval: 'for' loop from %s to %s never gets there with step %s",
+			values[0].Inspect(), values[1].Inspect(), strconv.FormatFloat(step, 'g', -1, 64))
+	}
+
+	// Each value is computed from the start rather than by adding up steps, so
+	// float steps such as 0.1 do not drift; epsilon lets the end be included.
+	epsilon := math.Abs(step) * 1e-9
+	for k := int64(0); ; k++ {
+		current := start + float64(k)*step
+		if (step > 0 && current > end+epsilon) || (step < 0 && current < end-epsilon) {
+			break
+		}
+
+		var value object.Object = &object.Float{Value: current}
+		if integers {
+			value = &object.Integer{Value: values[0].(*object.Integer).Value + k*int64(step)}
+		}
+		currentEnv := NewEnclosedEnvironment(env) // Create new scope for each iteration
+		currentEnv.Set(fs.Variable.Value, value)  // Bind loop variable
+		if done, result := runIteration(fs.Body, fs.Label, currentEnv); done {
+			return result
+		}
+	}
+
+	return object.NULL
 }
 
-// NewEnclosedEnvironment creates a new environment enclosed by outer environment.
-func NewEnclosedEnvironment(outer *Environment) *Environment {
-	env := NewEnvironment()
-	env.outer = outer
-	return env
+// isNumber reports whether obj is an integer or a float.
+func isNumber(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.Float:
+		return true
+	}
+	return false
+}
+
+// toFloat returns the value of a number as a float64.
+func toFloat(obj object.Object) float64 {
+	if i, ok := obj.(*object.Integer); ok {
+		return float64(i.Value)
+	}
+	return obj.(*object.Float).Value
 }
 
+// evalIncrementStatement adds the amount to, or subtracts it from, a variable
+// in the scope that defines it, or an item of a list in place.
+func evalIncrementStatement(is *ast.IncrementStatement, env *Environment) object.Object {
+	verb := is.Token.Literal
+
+	var amount object.Object = &object.Integer{Value: 1}
+	if is.Amount != nil {
+		amount = Eval(is.Amount, env)
+		if isUnwinding(amount) {
+			return amount
+		}
+		if !isNumber(amount) {
+			return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: cannot %s by %s, it is not a number", verb, amount.Type())
+		}
+	}
+
+	current, ok := env.Get(is.Name.Value)
+	if !ok {
+		return object.NewCodedError(diagnostic.CodeUndefinedName, "Eval: cannot %s '%s', it is not defined", verb, is.Name.Value)
+	}
+
+	update := func(value object.Object, what string) object.Object {
+		if !isNumber(value) {
+			return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: cannot %s %s, it holds %s, not a number", verb, what, value.Type())
+		}
+		if is.Token.Type == token.DECREMENT {
+			return evalSubtractInfixExpression("sub", value, amount)
+		}
+		return evalAddInfixExpression("add", value, amount)
+	}
+
+	if is.Index == nil {
+		result := update(current, "'"+is.Name.Value+"'")
+		if isUnwinding(result) {
+			return result
+		}
+		env.Assign(is.Name.Value, result)
+		return result
+	}
+
+	list, ok := current.(*object.List)
+	if !ok {
+		return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: '%s item at index' expected a list, got %s", verb, current.Type())
+	}
+	index, err := listIndex(list, Eval(is.Index, env), verb+" item at index")
+	if err != nil {
+		return err
+	}
 
+	result := update(list.Elements[index], fmt.Sprintf("item %d of '%s'", index, is.Name.Value))
+	if isUnwinding(result) {
+		return result
+	}
+	stored, rejected := list.Accept(result) // 'increment ... by 0.5' does not fit a numbers list
+	if rejected != nil {
+		return rejected
+	}
+	list.Elements[index] = stored
+	return stored
+}
+
+// evalLetStatement declares a name in the current scope. Inside a loop body or
+// a function this hides an outer binding of the same name rather than
+// changing it; 'let' again in the same scope simply rebinds the name.
 func evalLetStatement(ls *ast.LetStatement, env *Environment) object.Object {
 	val := Eval(ls.Value, env)
-	if isError(val) {
+	if isUnwinding(val) {
 		return val
 	}
 	env.Set(ls.Name.Value, val) // Store in the environment
 	return val
 }
 
+// evalSetStatement assigns to an existing binding, in whichever enclosing
+// scope declared it.
+func evalSetStatement(ss *ast.SetStatement, env *Environment) object.Object {
+	val := Eval(ss.Value, env)
+	if isUnwinding(val) {
+		return val
+	}
+	if !env.Assign(ss.Name.Value, val) {
+		return object.NewCodedError(diagnostic.CodeUndefinedName, "Eval: cannot set '%s', it was never declared (use 'let %s be ...' to declare it)", ss.Name.Value, ss.Name.Value)
+	}
+	return val
+}
+
 func evalReturnStatement(rs *ast.ReturnStatement, env *Environment) object.Object {
 	val := Eval(rs.ReturnValue, env)
-	if isError(val) {
+	if isUnwinding(val) {
 		return val
 	}
 	return &object.ReturnValue{Value: val} // Wrap in ReturnValue object
 }
 
+func evalFunctionStatement(fs *ast.FunctionStatement, env *Environment) object.Object {
+	fn := &object.Function{
+		Name:       fs.Name.Value,
+		Parameters: fs.Function.Parameters,
+		Body:       fs.Function.Body,
+		Env:        env, // The closure: the body sees the scope the function was declared in
+	}
+	env.Set(fs.Name.Value, fn)
+	return fn
+}
+
+func evalCallExpression(ce *ast.CallExpression, env *Environment) object.Object {
+	function := Eval(ce.Function, env)
+	if isUnwinding(function) {
+		return function
+	}
+
+	args := evalExpressions(ce.Arguments, env)
+	if len(args) == 1 && isUnwinding(args[0]) {
+		return args[0]
+	}
+
+	return applyFunction(function, args, env, ce.Token)
+}
+
+// ApplyFunction runs a function value with already evaluated arguments. env is
+// the caller's environment, which builtins receive.
+func ApplyFunction(fn object.Object, args []object.Object, env *Environment) object.Object {
+	return applyFunction(fn, args, env, token.Token{})
+}
+
+// applyFunction calls fn from the call site call, the zero Token for calls
+// made by the host. An error out of a WordLang function's body records the
+// call on its stack.
+func applyFunction(fn object.Object, args []object.Object, env *Environment, call token.Token) object.Object {
+	if err := checkCancelled(env); err != nil {
+		return err
+	}
+	if builtin, ok := fn.(*object.Builtin); ok {
+		return builtin.Fn(env, args...)
+	}
+
+	function, ok := fn.(*object.Function)
+	if !ok {
+		return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Not a function: %s", fn.Type())
+	}
+
+	if len(args) != len(function.Parameters) {
+		return object.NewCodedError(diagnostic.CodeArgumentCount, "Eval: Wrong number of arguments for %s: expected %d, got %d",
+			function.Inspect(), len(function.Parameters), len(args))
+	}
+
+	extendedEnv := NewEnclosedEnvironment(function.Env)
+	for i, param := range function.Parameters {
+		extendedEnv.Set(param.Value, args[i])
+	}
+
+	evaluated := Eval(function.Body, extendedEnv)
+	if err, ok := evaluated.(*object.Error); ok {
+		err.Stack = append(err.Stack, object.Frame{
+			Function: function.Name,
+			File:     env.File(),
+			Call:     diagnostic.Position{Line: call.Line, Column: call.Column},
+		})
+	}
+	return unwrapReturnValue(evaluated)
+}
+
+// unwrapReturnValue stops a 'return' at the call boundary so it doesn't unwind the caller too.
+func unwrapReturnValue(obj object.Object) object.Object {
+	if obj == nil {
+		return object.NULL // Empty function body
+	}
+	if returnValue, ok := obj.(*object.ReturnValue); ok {
+		return returnValue.Value
+	}
+	return obj
+}
+
 func evalIdentifier(node *ast.Identifier, env *Environment) object.Object {
 	val, ok := env.Get(node.Value)
 	if !ok {
-		return object.NewError("Eval: Identifier not found: %s", node.Value)
+		return object.NewCodedError(diagnostic.CodeUndefinedName, "Eval: Identifier not found: %s", node.Value)
 	}
 	return val
 }
 
 func evalPrintStatement(ps *ast.PrintStatement, env *Environment) object.Object {
 	value := Eval(ps.Value, env)
-	if isError(value) {
+	if isUnwinding(value) {
 		return value
 	}
-	fmt.Println(value.Inspect()) // Use Inspect for string representation
+	fmt.Fprintln(env.Session().Stdout, value.Inspect()) // Use Inspect for string representation
 	return object.NULL
 }
 
+// evalInputStatement shows the prompt, if any, and reads one line from the
+// session's input, without its line ending.
 func evalInputStatement(is *ast.InputStatement, env *Environment) object.Object {
-	var prompt string
+	session := env.Session()
 	if is.Prompt != nil {
-		prompt = is.Prompt.Value
-		fmt.Print(prompt)
+		fmt.Fprint(session.Stdout, is.Prompt.Value)
 	}
-	var input string
-	fmt.Scanln(&input) // Read a line of input
-	return &object.String{Value: input}
+	line, err := session.Stdin.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return object.NewError("Eval: input failed: %s", err)
+	}
+	return &object.String{Value: strings.TrimRight(line, "\r\n")}
+}
+
+// checkCancelled reports an error once the context of the session running env
+// is done, so that a host can stop a program stuck in a loop.
+func checkCancelled(env *Environment) *object.Error {
+	if err := env.Session().Context.Err(); err != nil {
+		return object.NewCodedError(diagnostic.CodeCancelled, "Eval: Execution cancelled: %s", err)
+	}
+	return nil
+}
+
+// listElementTypes maps the typed list keywords to their element types.
+var listElementTypes = map[token.TokenType]object.ObjectType{
+	token.STRINGS:  object.STRING_OBJ,
+	token.NUMBERS:  object.INTEGER_OBJ,
+	token.DECIMALS: object.FLOAT_OBJ,
 }
 
 func evalListLiteral(ll *ast.ListLiteral, env *Environment) object.Object {
 	elements := evalExpressions(ll.Elements, env)
-	if len(elements) > 0 && isError(elements[0]) { // Check for error in first element eval
+	if len(elements) > 0 && isUnwinding(elements[0]) { // Check for error in first element eval
 		return elements[0]
 	}
-	return &object.List{Elements: elements}
+
+	list := &object.List{Elements: elements, ElementType: listElementTypes[ll.Token.Type]}
+	for i, elem := range elements {
+		stored, err := list.Accept(elem)
+		if err != nil {
+			return err
+		}
+		elements[i] = stored
+	}
+	return list
 }
 
 func evalExpressions(exps []ast.Expression, env *Environment) []object.Object {
 	var results []object.Object
 	for _, exp := range exps {
 		evaluated := Eval(exp, env)
-		if isError(evaluated) {
+		if isUnwinding(evaluated) {
 			return []object.Object{evaluated} // Return error immediately
 		}
 		results = append(results, evaluated)
@@ -641,28 +1186,38 @@ func evalExpressions(exps []ast.Expression, env *Environment) []object.Object {
 
 func evalGetItemAtIndexExpression(giae *ast.GetItemAtIndexExpression, env *Environment) object.Object {
 	listObj := Eval(giae.List, env)
-	if isError(listObj) {
+	if isUnwinding(listObj) {
 		return listObj
 	}
 	list, ok := listObj.(*object.List)
 	if !ok {
-		return object.NewError("Eval: 'get item at index' expected a list, got %s", listObj.Type())
+		return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: 'get item at index' expected a list, got %s", listObj.Type())
 	}
 
-	indexObj := Eval(giae.Index, env)
-	if isError(indexObj) {
-		return indexObj
+	index, err := listIndex(list, Eval(giae.Index, env), "get item at index")
+	if err != nil {
+		return err
+	}
+
+	return list.Elements[index]
+}
+
+// listIndex checks that indexObj, the evaluated index of an operation on
+// list, is an integer naming one of its elements. op names the operation in
+// errors. A signal already unwinding through indexObj is returned as the error.
+func listIndex(list *object.List, indexObj object.Object, op string) (int, object.Object) {
+	if isUnwinding(indexObj) {
+		return 0, indexObj
 	}
 	index, ok := indexObj.(*object.Integer)
 	if !ok {
-		return object.NewError("Eval: 'get item at index' index must be a number, got %s", indexObj.Type())
+		return 0, object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: '%s' index must be a number, got %s", op, indexObj.Type())
 	}
 
 	if index.Value < 0 || index.Value >= int64(len(list.Elements)) {
-		return object.NewError("Eval: Index out of bounds: %d, list length: %d", index.Value, len(list.Elements))
+		return 0, object.NewCodedError(diagnostic.CodeIndexOutOfRange, "Eval: Index out of bounds: %d, list length: %d", index.Value, len(list.Elements))
 	}
-
-	return list.Elements[index.Value]
+	return int(index.Value), nil
 }
 
 func evalIsDefinedExpression(ide *ast.IsDefinedExpression, env *Environment) object.Object {
@@ -670,27 +1225,26 @@ func evalIsDefinedExpression(ide *ast.IsDefinedExpression, env *Environment) obj
 	return nativeBoolToBooleanObject(ok) // Returns true if defined, false otherwise
 }
 
+// evalExitStatement sends the exit signal with the program's exit code, 0 by default.
 func evalExitStatement(es *ast.ExitStatement, env *Environment) object.Object {
-	code := 0 // Default exit code
-	if es.Code != nil {
-		codeObj := Eval(es.Code, env)
-		if isError(codeObj) {
-			fmt.Println(codeObj.Inspect()) // Print error before exiting
-			code = 1 // Error exit code in case of evaluation error
-		} else if intCode, ok := codeObj.(*object.Integer); ok {
-			code = int(intCode.Value)
-		} else {
-			fmt.Println(object.NewError("Eval: Exit code must be an integer, got %s", codeObj.Type()).Inspect())
-			code = 1
-		}
+	if es.Code == nil {
+		return &object.Exit{Code: 0}
+	}
+
+	codeObj := Eval(es.Code, env)
+	if isUnwinding(codeObj) {
+		return codeObj
+	}
+	code, ok := codeObj.(*object.Integer)
+	if !ok {
+		return object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: Exit code must be an integer, got %s", codeObj.Type())
 	}
-	os.Exit(code)
-	return object.NULL // Should not reach here
+	return &object.Exit{Code: int(code.Value)}
 }
 
 func evalConvertToNumberExpression(ctne *ast.ConvertToNumberExpression, env *Environment) object.Object {
 	expValue := Eval(ctne.Expression, env)
-	if isError(expValue) {
+	if isUnwinding(expValue) {
 		return expValue
 	}
 
@@ -716,16 +1270,19 @@ func evalConvertToNumberExpression(ctne *ast.ConvertToNumberExpression, env *Env
 
 func evalConvertToStringExpression(ctse *ast.ConvertToStringExpression, env *Environment) object.Object {
 	expValue := Eval(ctse.Expression, env)
-	if isError(expValue) {
+	if isUnwinding(expValue) {
 		return expValue
 	}
 	return &object.String{Value: expValue.Inspect()} // Use Inspect() to get string representation
 }
 
 
-func isError(obj object.Object) bool {
+// isUnwinding reports whether obj must be passed straight up to the caller:
+// a runtime error, or the signal of an 'exit' on its way to the top level.
+func isUnwinding(obj object.Object) bool {
 	if obj != nil {
-		return obj.Type() == object.ERROR_OBJ
+		t := obj.Type()
+		return t == object.ERROR_OBJ || t == object.EXIT_OBJ
 	}
 	return false
 }
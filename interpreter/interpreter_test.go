@@ -0,0 +1,378 @@
+package interpreter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"wordlang/ast"
+	"wordlang/diagnostic"
+	"wordlang/lexer"
+	"wordlang/object"
+	"wordlang/parser"
+)
+
+// run evaluates input in a fresh environment and returns what it printed and
+// the result of the program.
+func run(t *testing.T, input string) (string, object.Object) {
+	t.Helper()
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if errs := append(l.Errors(), p.Errors()...); len(errs) != 0 {
+		t.Fatalf("%q: parse errors %v", input, errs)
+	}
+
+	var out bytes.Buffer
+	session := object.NewSession()
+	session.Stdout = &out
+	result := Eval(program, session.NewEnvironment(""))
+	return out.String(), result
+}
+
+func TestRemoveDuringForEach(t *testing.T) {
+	out, result := run(t, "let nums be list 1 2 3 2\nforeach n in nums do\nprint n\nremove n from nums\nendforeach\nprint nums")
+	if err, ok := result.(*object.Error); ok {
+		t.Fatalf("unexpected error %s", err.Message)
+	}
+	if want := "1\n2\n3\n2\n[]\n"; out != want {
+		t.Errorf("got output %q, want %q", out, want)
+	}
+}
+
+func TestAppendDuringForEach(t *testing.T) {
+	out, _ := run(t, "let nums be list 1 2\nforeach n in nums do\nappend n to nums\nendforeach\nprint nums")
+	if want := "[1, 2, 1, 2]\n"; out != want {
+		t.Errorf("got output %q, want %q", out, want)
+	}
+}
+
+func TestErrorPosition(t *testing.T) {
+	_, result := run(t, "let a be 1\nprint  missing")
+	err, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("got %v, want an error", result)
+	}
+	want := diagnostic.Position{Line: 2, Column: 8}
+	if err.Span.Start != want {
+		t.Errorf("error at %v, want %v", err.Span.Start, want)
+	}
+}
+
+// TestErrorFile checks that a runtime error names the file as it was given,
+// the way parse errors do, rather than its absolute path.
+func TestErrorFile(t *testing.T) {
+	program := parser.New(lexer.New("print div 1 by 0")).ParseProgram()
+	result := Eval(program, NewFileEnvironment(object.NewSession(), "r.wl"))
+	err, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("got %v, want an error", result)
+	}
+	if err.File != "r.wl" {
+		t.Errorf("error in %q, want %q", err.File, "r.wl")
+	}
+}
+
+// TestErrorWithoutPosition evaluates a tree built by hand, whose nodes have no
+// positions; the error must come back unlocated rather than crash.
+func TestErrorWithoutPosition(t *testing.T) {
+	program := &ast.Program{Statements: []ast.Statement{
+		&ast.ExpressionStatement{Expression: &ast.Identifier{Value: "missing"}},
+	}}
+	result := Eval(program, NewEnvironment())
+	err, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("got %v, want an error", result)
+	}
+	if err.Span.Start.Line != 0 {
+		t.Errorf("got position %v, want none", err.Span.Start)
+	}
+	if Eval(nil, NewEnvironment()) == nil {
+		t.Errorf("Eval(nil) returned nil, want an error")
+	}
+}
+
+func TestCallStack(t *testing.T) {
+	_, result := run(t, "function inner\nprint missing\nendfunction\nfunction outer\ncall inner\nendfunction\ncall outer")
+	err, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("got %v, want an error", result)
+	}
+	if len(err.Stack) != 2 || err.Stack[0].Function != "inner" || err.Stack[1].Function != "outer" {
+		t.Fatalf("got stack %v, want inner then outer", err.Stack)
+	}
+	if err.Stack[0].Call.Line != 5 || err.Stack[1].Call.Line != 7 {
+		t.Errorf("got call sites %v and %v, want lines 5 and 7", err.Stack[0].Call, err.Stack[1].Call)
+	}
+}
+
+// TestBareExit checks that a bare 'exit' stops the program with code 0 from
+// inside an if, a loop and a function.
+func TestBareExit(t *testing.T) {
+	tests := []string{
+		"let x be 1\nif x equals 1 then\n  exit\nendif\nprint 2",
+		"while true do\nprint 1\nexit\nendwhile\nprint 2",
+		"function f\nprint 1\nexit\nend\ncall f\nprint 2",
+	}
+
+	for _, input := range tests {
+		out, result := run(t, input)
+		exit, ok := result.(*object.Exit)
+		if !ok || exit.Code != 0 {
+			t.Errorf("%q: got %v, want exit 0", input, result)
+		}
+		if strings.Contains(out, "2") {
+			t.Errorf("%q: kept running after exit, printed %q", input, out)
+		}
+	}
+}
+
+// TestImportSyntaxErrors checks that a module with lexer or parser errors is
+// not run, and that its errors are reported with the module's file and position.
+func TestImportSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		module string
+		codes  []string
+	}{
+		{"let a be \"bad \\q escape\"", []string{diagnostic.CodeInvalidEscape}},
+		{"let a be 1\n/* open", []string{diagnostic.CodeUnterminatedComment}},
+		{"let a be 1 @", []string{diagnostic.CodeIllegalCharacter}},
+		{"let a 1", []string{diagnostic.CodeUnexpectedToken}},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		module := filepath.Join(dir, "m.wl")
+		if err := os.WriteFile(module, []byte(tt.module), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		program := parser.New(lexer.New("from m import a")).ParseProgram()
+		result := Eval(program, NewFileEnvironment(object.NewSession(), filepath.Join(dir, "main.wl")))
+
+		err, ok := result.(*object.Error)
+		if !ok || err.Code != diagnostic.CodeImport {
+			t.Errorf("%q: got %v, want an import error", tt.module, result)
+			continue
+		}
+		if len(err.Causes) != len(tt.codes) {
+			t.Errorf("%q: got causes %v, want codes %v", tt.module, err.Causes, tt.codes)
+			continue
+		}
+		for i, d := range err.Causes {
+			if d.Code != tt.codes[i] || d.File != module || d.Span.Start.Line == 0 {
+				t.Errorf("%q: cause %d is %s in %s at %d:%d, want %s in %s", tt.module, i, d.Code, d.File, d.Span.Start.Line, d.Span.Start.Column, tt.codes[i], module)
+			}
+		}
+	}
+}
+
+// TestTryErrorName checks the message bound by 'if it fails as', which is the
+// one the program would want to show, without the interpreter's prefix.
+func TestTryErrorName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"try\nlet n be convert to number \"abc\"\nif it fails as err\nprint \"failed: {err}\"\nendtry", "failed: Cannot convert string 'abc' to number"},
+		{"try\nraise \"boom\"\nif it fails as err\nprint err\nendtry", "boom"},
+		{"try\nprint missing\nif it fails as err\nprint err\nendtry", "Identifier not found: missing"},
+	}
+
+	for _, tt := range tests {
+		out, result := run(t, tt.input)
+		if err, ok := result.(*object.Error); ok {
+			t.Errorf("%q: unexpected error %s", tt.input, err.Message)
+			continue
+		}
+		if !strings.HasPrefix(out, tt.want) {
+			t.Errorf("%q: printed %q, want it to start with %q", tt.input, out, tt.want)
+		}
+	}
+}
+
+// TestLoopControl runs 'stop' and 'skip', plain and labelled, in every kind of loop.
+func TestLoopControl(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"let i be 0\nwhile true do\nincrement i\nif i equals 2 then\nskip\nendif\nif i equals 4 then\nstop\nendif\nprint i\nendwhile", "1\n3\n"},
+		{"foreach n in list 1 2 3 4 end do\nif n equals 2 then\nskip to next\nendif\nif n equals 4 then\nstop loop\nendif\nprint n\nendforeach", "1\n3\n"},
+		{"let i be 0\nrepeat 5 times do\nincrement i\nif i equals 2 then\nskip\nendif\nif i equals 4 then\nstop\nendif\nprint i\nendrepeat", "1\n3\n"},
+		{"for i from 1 to 5 do\nif i equals 2 then\nskip\nendif\nif i equals 4 then\nstop\nendif\nprint i\nendfor", "1\n3\n"},
+		{"for i from 1 to 3 named outer do\nrepeat 3 times do\nif i equals 2 then\nskip outer\nendif\nif i equals 3 then\nstop outer\nendif\nprint i\nendrepeat\nendfor", "1\n1\n1\n"},
+		{"foreach a in list 1 2 end named outer do\nlet j be 0\nwhile true do\nstop outer\nendwhile\nendforeach\nprint \"done\"", "done\n"},
+		{"function f\nfor i from 1 to 3 do\nreturn i\nendfor\nend\nprint call f", "1\n"},
+	}
+
+	for _, tt := range tests {
+		out, result := run(t, tt.input)
+		if err, ok := result.(*object.Error); ok {
+			t.Errorf("%q: unexpected error %s", tt.input, err.Message)
+			continue
+		}
+		if out != tt.want {
+			t.Errorf("%q: printed %q, want %q", tt.input, out, tt.want)
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"let name be \"Ann\"\nlet n be 3\nprint \"Hi {name}, {add n and 1} items\"", "Hi Ann, 4 items\n"},
+		{"print \"brace \\{ok\\} tab\\tend\"", "brace {ok} tab\tend\n"},
+		{"print \"{list 1 2 end}\"", "[1, 2]\n"},
+		{"print \"line\\none\"", "line\none\n"},
+	}
+
+	for _, tt := range tests {
+		out, result := run(t, tt.input)
+		if err, ok := result.(*object.Error); ok {
+			t.Errorf("%q: unexpected error %s", tt.input, err.Message)
+			continue
+		}
+		if out != tt.want {
+			t.Errorf("%q: printed %q, want %q", tt.input, out, tt.want)
+		}
+	}
+
+	_, result := run(t, "print \"{missing}\"")
+	if err, ok := result.(*object.Error); !ok || err.Code != diagnostic.CodeUndefinedName {
+		t.Errorf("got %v, want an undefined name error", result)
+	}
+}
+
+func TestCountedLoops(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"repeat 3 times do\nprint \"hi\"\nendrepeat", "hi\nhi\nhi\n"},
+		{"repeat 0 times do\nprint \"never\"\nendrepeat", ""},
+		{"for i from 1 to 3 do\nprint i\nendfor", "1\n2\n3\n"},
+		{"for i from 3 to 1 do\nprint i\nendfor", "3\n2\n1\n"},
+		{"for i from 0 to 10 step 5 do\nprint i\nendfor", "0\n5\n10\n"},
+		{"for i from 1 to 2 step 0.5 do\nprint i\nendfor", "1.000000\n1.500000\n2.000000\n"},
+		{"for i from 1 to 2 do\nlet x be i\nendfor\nprint is defined x", "false\n"},
+	}
+
+	for _, tt := range tests {
+		out, result := run(t, tt.input)
+		if err, ok := result.(*object.Error); ok {
+			t.Errorf("%q: unexpected error %s", tt.input, err.Message)
+			continue
+		}
+		if out != tt.want {
+			t.Errorf("%q: printed %q, want %q", tt.input, out, tt.want)
+		}
+	}
+
+	for _, input := range []string{
+		"repeat -1 times do\nendrepeat",
+		"repeat \"x\" times do\nendrepeat",
+		"for i from 1 to 3 step 0 do\nendfor",
+		"for i from 1 to 3 step -1 do\nendfor",
+	} {
+		if _, result := run(t, input); result.Type() != object.ERROR_OBJ {
+			t.Errorf("%q: got %v, want an error", input, result)
+		}
+	}
+}
+
+func TestWhen(t *testing.T) {
+	program := "when n\nis 1 or 2\nprint \"small\"\nis between 3 and 5\nprint \"mid\"\notherwise\nprint \"big\"\nendwhen"
+	tests := []struct {
+		n    string
+		want string
+	}{
+		{"1", "small\n"},
+		{"2", "small\n"},
+		{"3", "mid\n"},
+		{"5", "mid\n"},
+		{"9", "big\n"},
+	}
+
+	for _, tt := range tests {
+		input := "let n be " + tt.n + "\n" + program
+		out, result := run(t, input)
+		if err, ok := result.(*object.Error); ok {
+			t.Errorf("%q: unexpected error %s", input, err.Message)
+			continue
+		}
+		if out != tt.want {
+			t.Errorf("n = %s: printed %q, want %q", tt.n, out, tt.want)
+		}
+	}
+
+	out, _ := run(t, "when \"x\"\nis \"y\"\nprint 1\nendwhen\nprint \"after\"")
+	if out != "after\n" {
+		t.Errorf("with no matching arm and no otherwise: printed %q, want %q", out, "after\n")
+	}
+}
+
+func TestTryAndRaise(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"try\nraise 42\nif it fails as e\nprint e\nalways\nprint \"cleanup\"\nendtry", "42\ncleanup\n"},
+		{"try\nprint 1\nif it fails\nprint 2\nendtry", "1\n"},
+		{"try\nprint div 1 by 0\nif it fails\nprint \"caught\"\nendtry\nprint \"after\"", "caught\nafter\n"},
+		{"function f\ntry\nreturn 1\nalways\nprint \"always\"\nendtry\nend\nprint call f", "always\n1\n"},
+		{"try\ntry\nraise \"inner\"\nendtry\nif it fails as e\nprint e\nendtry", "inner\n"},
+	}
+
+	for _, tt := range tests {
+		out, result := run(t, tt.input)
+		if err, ok := result.(*object.Error); ok {
+			t.Errorf("%q: unexpected error %s", tt.input, err.Message)
+			continue
+		}
+		if out != tt.want {
+			t.Errorf("%q: printed %q, want %q", tt.input, out, tt.want)
+		}
+	}
+
+	_, result := run(t, "try\nraise \"x\"\nendtry")
+	if err, ok := result.(*object.Error); !ok || err.Code != diagnostic.CodeRaised || err.Message != "x" {
+		t.Errorf("got %v, want the raised error", result)
+	}
+}
+
+func TestDictionaries(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"let d be dictionary with \"a\" as 1 and \"b\" as 2\nprint d\nprint value for \"a\" in d", "{a: 1, b: 2}\n1\n"},
+		{"let d be dictionary\nset value for 1 in d to \"one\"\nprint d has key 1\nprint d has key \"1\"", "true\nfalse\n"},
+		{"let d be dictionary with \"a\" as 1 and \"b\" as 2\nremove \"a\" from d\nprint d", "{b: 2}\n"},
+		{"let d be dictionary with \"b\" as 1 and \"a\" as 2\nforeach k in d do\nprint k\nendforeach", "b\na\n"},
+		{"let d be dictionary\nlet e be d\nset value for \"z\" in e to 0\nprint d", "{z: 0}\n"},
+	}
+
+	for _, tt := range tests {
+		out, result := run(t, tt.input)
+		if err, ok := result.(*object.Error); ok {
+			t.Errorf("%q: unexpected error %s", tt.input, err.Message)
+			continue
+		}
+		if out != tt.want {
+			t.Errorf("%q: printed %q, want %q", tt.input, out, tt.want)
+		}
+	}
+
+	for _, input := range []string{
+		"let d be dictionary\nprint value for \"q\" in d",
+		"let d be dictionary\nremove \"q\" from d",
+	} {
+		_, result := run(t, input)
+		if err, ok := result.(*object.Error); !ok || err.Code != diagnostic.CodeMissingKey {
+			t.Errorf("%q: got %v, want a missing key error", input, result)
+		}
+	}
+}
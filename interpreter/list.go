@@ -0,0 +1,121 @@
+package interpreter
+
+import (
+	"wordlang/ast"
+	"wordlang/diagnostic"
+	"wordlang/object"
+)
+
+// The list statements change an object.List in place. Typed lists check what
+// is stored.
+
+func evalAppendStatement(as *ast.AppendStatement, env *Environment) object.Object {
+	list, err := evalList(as.List, env, "append")
+	if err != nil {
+		return err
+	}
+	value, err := evalListValue(as.Value, list, env)
+	if err != nil {
+		return err
+	}
+
+	list.Elements = append(list.Elements, value)
+	return object.NULL
+}
+
+func evalInsertStatement(is *ast.InsertStatement, env *Environment) object.Object {
+	list, err := evalList(is.List, env, "insert")
+	if err != nil {
+		return err
+	}
+
+	indexObj := Eval(is.Index, env)
+	index := len(list.Elements)
+	if end, ok := indexObj.(*object.Integer); !ok || end.Value != int64(index) { // Inserting just past the end appends
+		index, err = listIndex(list, indexObj, "insert at index")
+		if err != nil {
+			return err
+		}
+	}
+
+	value, err := evalListValue(is.Value, list, env)
+	if err != nil {
+		return err
+	}
+
+	list.Elements = append(list.Elements, nil)
+	copy(list.Elements[index+1:], list.Elements[index:])
+	list.Elements[index] = value
+	return object.NULL
+}
+
+func evalSetItemStatement(sis *ast.SetItemStatement, env *Environment) object.Object {
+	list, err := evalList(sis.List, env, "set item at index")
+	if err != nil {
+		return err
+	}
+	index, err := listIndex(list, Eval(sis.Index, env), "set item at index")
+	if err != nil {
+		return err
+	}
+	value, err := evalListValue(sis.Value, list, env)
+	if err != nil {
+		return err
+	}
+
+	list.Elements[index] = value
+	return value
+}
+
+// removeFromList handles 'remove' on a list: by position for 'remove item at
+// index', otherwise the first item equal to the value.
+func removeFromList(rs *ast.RemoveStatement, list *object.List, env *Environment) object.Object {
+	if rs.Index != nil {
+		index, err := listIndex(list, Eval(rs.Index, env), "remove item at index")
+		if err != nil {
+			return err
+		}
+		list.Elements = append(list.Elements[:index], list.Elements[index+1:]...)
+		return object.NULL
+	}
+
+	value := Eval(rs.Value, env)
+	if isUnwinding(value) {
+		return value
+	}
+	for i, elem := range list.Elements {
+		if isTruthy(evalEqualsInfixExpression("equals", elem, value)) {
+			list.Elements = append(list.Elements[:i], list.Elements[i+1:]...)
+			return object.NULL
+		}
+	}
+	return object.NewError("Eval: cannot remove %s, the list does not contain it", value.Inspect())
+}
+
+// evalList evaluates an expression that must give a list. op names the
+// operation in errors.
+func evalList(node ast.Expression, env *Environment, op string) (*object.List, object.Object) {
+	obj := Eval(node, env)
+	if isUnwinding(obj) {
+		return nil, obj
+	}
+	list, ok := obj.(*object.List)
+	if !ok {
+		return nil, object.NewCodedError(diagnostic.CodeTypeMismatch, "Eval: '%s' expected a list, got %s", op, obj.Type())
+	}
+	return list, nil
+}
+
+// evalListValue evaluates a value to be stored in list and checks it against
+// the list's element type.
+func evalListValue(node ast.Expression, list *object.List, env *Environment) (object.Object, object.Object) {
+	value := Eval(node, env)
+	if isUnwinding(value) {
+		return nil, value
+	}
+	stored, rejected := list.Accept(value)
+	if rejected != nil {
+		return nil, rejected
+	}
+	return stored, nil
+}
@@ -0,0 +1,136 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"wordlang/ast"
+	"wordlang/builtins"
+	"wordlang/diagnostic"
+	"wordlang/object"
+	"wordlang/parser"
+)
+
+// NewFileEnvironment creates the top-level environment in session for running
+// the file at path. Imports are resolved relative to the file, then along the
+// session's search path. The file counts as being loaded, so a module
+// importing it back is reported as a cycle. Diagnostics show path as given.
+func NewFileEnvironment(session *object.Session, path string) *Environment {
+	session.Loading = []string{moduleKey(path)}
+	return session.NewEnvironment(path)
+}
+
+// moduleKey is the absolute form of a module's path, which identifies the
+// module however it was reached. Diagnostics show the path as resolved instead.
+func moduleKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// evalImportStatement loads the module and binds only the requested names in env.
+func evalImportStatement(is *ast.ImportStatement, env *Environment) object.Object {
+	module, stop := loadModule(is.Module, env)
+	if stop != nil {
+		return stop
+	}
+
+	for _, name := range is.Names {
+		val, ok := module.Env.Get(name.Value)
+		if !ok {
+			return object.NewCodedError(diagnostic.CodeImport, "Eval: Module %s has no name '%s'", module.Name, name.Value)
+		}
+		env.Set(name.Value, val)
+	}
+
+	return object.NULL
+}
+
+// loadModule returns the named module, loading and caching it on first use.
+// If the module fails to load, or runs 'exit' while loading, the error or
+// exit signal is returned instead for the importer to pass on.
+func loadModule(name string, env *Environment) (*object.Module, object.Object) {
+	session := env.Session()
+
+	if fns, ok := builtins.Module(name); ok {
+		if module, ok := session.Modules[name]; ok {
+			return module, nil
+		}
+		module := &object.Module{Name: name, Env: session.NewEnvironment("")}
+		for fnName, fn := range fns {
+			module.Env.Set(fnName, fn)
+		}
+		session.Modules[name] = module
+		return module, nil
+	}
+
+	path, err := resolveModulePath(name, env.File(), session.SearchPath)
+	if err != nil {
+		return nil, object.NewCodedError(diagnostic.CodeImport, "Eval: Cannot import %s: %s", name, err)
+	}
+
+	key := moduleKey(path)
+	if module, ok := session.Modules[key]; ok {
+		return module, nil
+	}
+
+	for i, loading := range session.Loading {
+		if loading == key {
+			cycle := append(append([]string{}, session.Loading[i:]...), key)
+			return nil, object.NewCodedError(diagnostic.CodeImport, "Eval: Import cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	session.Loading = append(session.Loading, key)
+	defer func() { session.Loading = session.Loading[:len(session.Loading)-1] }()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, object.NewCodedError(diagnostic.CodeImport, "Eval: Cannot import %s: %s", name, err)
+	}
+
+	program, diagnostics := parser.Parse(path, string(content))
+	if len(diagnostics) != 0 {
+		err := object.NewCodedError(diagnostic.CodeImport, "Eval: Cannot import %s: %s has syntax errors", name, path)
+		err.Causes = diagnostics
+		return nil, err
+	}
+
+	module := &object.Module{Name: name, Path: path, Env: session.NewEnvironment(path)}
+	if result := Eval(program, module.Env); isUnwinding(result) {
+		return nil, result
+	}
+
+	session.Modules[key] = module
+	return module, nil
+}
+
+// resolveModulePath finds the file for a module name: first next to the
+// importing file (or in the working directory), then in each search path entry.
+func resolveModulePath(name, importer string, searchPath []string) (string, error) {
+	file := name
+	if filepath.Ext(file) != ".wl" {
+		file += ".wl"
+	}
+
+	if filepath.IsAbs(file) {
+		return file, nil
+	}
+
+	dirs := []string{"."}
+	if importer != "" {
+		dirs[0] = filepath.Dir(importer)
+	}
+	dirs = append(dirs, searchPath...)
+
+	for _, dir := range dirs {
+		candidate := filepath.Join(dir, file)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no module file %s in %s", file, strings.Join(dirs, ", "))
+}
@@ -1,46 +1,78 @@
 package lexer
 
 import (
+	"fmt"
+	"strings"
 	"unicode"
+	"unicode/utf8"
+	"wordlang/diagnostic"
 	"wordlang/token"
 )
 
-// Lexer holds the state for lexing.
+// Lexer holds the state for lexing. The input is decoded as UTF-8; positions
+// are byte offsets into input, while line and column count characters.
 type Lexer struct {
 	input        string
 	position     int     // current position in input (points to current char)
 	readPosition int     // next reading position in input (after current char)
-	ch           byte    // current char under examination
+	ch           rune    // current char under examination
 	line         int     // current line number
 	column       int     // current column number
-	errors       []string // Lexer errors
+	errors       []*diagnostic.Diagnostic // Lexer errors
 }
 
 // New creates a new Lexer.
 func New(input string) *Lexer {
-	l := &Lexer{input: input, line: 1, column: 1}
+	return NewAt(input, 1, 1)
+}
+
+// NewAt creates a Lexer for input that starts at line and column of a larger
+// source, so that positions of its tokens and errors refer to that source.
+func NewAt(input string, line, column int) *Lexer {
+	l := &Lexer{input: input, line: line, column: column - 1}
 	l.readChar() // Initialize lexer
 	return l
 }
 
-// readChar reads the next character and advances the position.
+// readChar decodes the next character and advances past it. Leaving a newline
+// moves the lexer to the start of the next line.
 func (l *Lexer) readChar() {
-	if l.readPosition >= len(l.input) {
-		l.ch = 0 // ASCII code for "NUL" character, signals EOF
-	} else {
-		l.ch = l.input[l.readPosition]
+	if l.readPosition > len(l.input) {
+		return // already at EOF
+	}
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
 	}
 	l.position = l.readPosition
-	l.readPosition++
-	l.column++ // Increment column on character read
+	if l.position == len(l.input) {
+		l.ch = 0 // NUL signals EOF
+		l.readPosition++
+	} else {
+		r, width := utf8.DecodeRuneInString(l.input[l.position:])
+		l.ch = r
+		l.readPosition += width
+	}
+	l.column++ // Columns count characters, not bytes
 }
 
 // peekChar looks at the next character without advancing.
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
 	}
-	return l.input[l.readPosition]
+	r, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	return r
+}
+
+// atInvalidRune reports whether the current character is a byte sequence
+// that is not valid UTF-8.
+func (l *Lexer) atInvalidRune() bool {
+	if l.ch != utf8.RuneError || l.position >= len(l.input) {
+		return false
+	}
+	_, width := utf8.DecodeRuneInString(l.input[l.position:])
+	return width == 1
 }
 
 // NextToken returns the next token from the input.
@@ -48,104 +80,172 @@ func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
 
 	l.skipWhitespace()
+	for l.ch == '/' && l.peekChar() == '*' {
+		l.skipBlockComment()
+		l.skipWhitespace()
+	}
+	line, column := l.line, l.column // Every token is positioned at its first character
 
 	switch l.ch {
 	case 0:
-		tok = newToken(token.EOF, l.ch)
-		tok.Line = l.line
-		tok.Column = l.column
+		tok = token.Token{Type: token.EOF, Line: line, Column: column}
 	case '#':
-		l.readComment()
+		if l.peekChar() == '#' {
+			return l.readDocComment()
+		}
+		l.skipLineComment()
 		return l.NextToken() // Skip comment
 	case '"':
 		tok = l.readString()
+	case '-':
+		tok = token.Token{Type: token.MINUS, Literal: "-", Line: line, Column: column}
 	default:
-		if unicode.IsLetter(rune(l.ch)) {
+		if isLetter(l.ch) {
 			ident := l.readIdentifier()
 			// Check for multi-word keywords *immediately* after reading an identifier
 			switch ident {
 			case "greater":
 				if l.peekKeyword("or") { // Check for "greater or"
-					l.readIdentifier() // Consume "or"
+					l.readNextWord() // Consume "or"
 					if l.peekKeyword("equal") { // Check for "greater or equal"
-						l.readIdentifier() // Consume "equal"
-						return token.Token{Type: token.GREATEREQUAL, Literal: "greater or equal", Line: l.line, Column: l.column - len("greater or equal") + 1}
+						l.readNextWord() // Consume "equal"
+						return token.Token{Type: token.GREATEREQUAL, Literal: "greater or equal", Line: line, Column: column}
 					}
-					return token.Token{Type: token.OR, Literal: "or", Line: l.line, Column: l.column - len("or") + 1} // Just "greater or" is treated as "or" keyword (might need refinement)
+					return token.Token{Type: token.OR, Literal: "or", Line: line, Column: column} // Just "greater or" is treated as "or" keyword (might need refinement)
 				} else if l.peekKeyword("than"){ // Check for "greater than"
-					l.readIdentifier() // Consume "than"
-					return token.Token{Type: token.GREATERTHAN, Literal: "greater than", Line: l.line, Column: l.column - len("greater than") + 1}
+					l.readNextWord() // Consume "than"
+					return token.Token{Type: token.GREATERTHAN, Literal: "greater than", Line: line, Column: column}
 				}
-				return token.Token{Type: token.GREATERTHAN, Literal: "greater", Line: l.line, Column: l.column - len("greater") + 1} // Just "greater" is treated as "greater than" keyword (might need refinement)
+				return token.Token{Type: token.GREATERTHAN, Literal: "greater", Line: line, Column: column} // Just "greater" is treated as "greater than" keyword (might need refinement)
 			case "less":
 				if l.peekKeyword("or") { // Check for "less or"
-					l.readIdentifier() // Consume "or"
+					l.readNextWord() // Consume "or"
 					if l.peekKeyword("equal") { // Check for "less or equal"
-						l.readIdentifier() // Consume "equal"
-						return token.Token{Type: token.LESSEQUAL, Literal: "less or equal", Line: l.line, Column: l.column - len("less or equal") + 1}
+						l.readNextWord() // Consume "equal"
+						return token.Token{Type: token.LESSEQUAL, Literal: "less or equal", Line: line, Column: column}
 					}
-					return token.Token{Type: token.OR, Literal: "or", Line: l.line, Column: l.column - len("or") + 1} // Just "less or" is treated as "or" keyword (might need refinement)
+					return token.Token{Type: token.OR, Literal: "or", Line: line, Column: column} // Just "less or" is treated as "or" keyword (might need refinement)
 				} else if l.peekKeyword("than"){ // Check for "less than"
-					l.readIdentifier() // Consume "than"
-					return token.Token{Type: token.LESSTHAN, Literal: "less than", Line: l.line, Column: l.column - len("less than") + 1}
+					l.readNextWord() // Consume "than"
+					return token.Token{Type: token.LESSTHAN, Literal: "less than", Line: line, Column: column}
 				}
-				return token.Token{Type: token.LESSTHAN, Literal: "less", Line: l.line, Column: l.column - len("less") + 1} // Just "less" is treated as "less than" keyword (might need refinement)
+				return token.Token{Type: token.LESSTHAN, Literal: "less", Line: line, Column: column} // Just "less" is treated as "less than" keyword (might need refinement)
 			case "end":
 				if l.peekKeyword("if") {
-					l.readIdentifier()
-					return token.Token{Type: token.ENDIF, Literal: "endif", Line: l.line, Column: l.column - len("endif") + 1}
+					l.readNextWord()
+					return token.Token{Type: token.ENDIF, Literal: "endif", Line: line, Column: column}
 				} else if l.peekKeyword("while") {
-					l.readIdentifier()
-					return token.Token{Type: token.ENDWHILE, Literal: "endwhile", Line: l.line, Column: l.column - len("endwhile") + 1}
+					l.readNextWord()
+					return token.Token{Type: token.ENDWHILE, Literal: "endwhile", Line: line, Column: column}
 				} else if l.peekKeyword("foreach") {
-					l.readIdentifier()
-					return token.Token{Type: token.ENDFOREACH, Literal: "endforeach", Line: l.line, Column: l.column - len("endforeach") + 1}
+					l.readNextWord()
+					return token.Token{Type: token.ENDFOREACH, Literal: "endforeach", Line: line, Column: column}
 				} else if l.peekKeyword("function") {
-					l.readIdentifier()
-					return token.Token{Type: token.ENDFUNCTION, Literal: "end function", Line: l.line, Column: l.column - len("end function") + 1}
+					l.readNextWord()
+					return token.Token{Type: token.ENDFUNCTION, Literal: "end function", Line: line, Column: column}
+				} else if l.peekKeyword("group") {
+					l.readNextWord()
+					return token.Token{Type: token.ENDGROUP, Literal: "end group", Line: line, Column: column}
+				} else if l.peekKeyword("repeat") {
+					l.readNextWord()
+					return token.Token{Type: token.ENDREPEAT, Literal: "end repeat", Line: line, Column: column}
+				} else if l.peekKeyword("for") {
+					l.readNextWord()
+					return token.Token{Type: token.ENDFOR, Literal: "end for", Line: line, Column: column}
+				} else if l.peekKeyword("when") {
+					l.readNextWord()
+					return token.Token{Type: token.ENDWHEN, Literal: "end when", Line: line, Column: column}
+				} else if l.peekKeyword("try") {
+					l.readNextWord()
+					return token.Token{Type: token.ENDTRY, Literal: "end try", Line: line, Column: column}
+				}
+				return token.Token{Type: token.END, Literal: "end", Line: line, Column: column} // Just "end"
+			case "if":
+				if l.peekKeyword("it", "fails") {
+					l.readNextWord()
+					l.readNextWord()
+					return token.Token{Type: token.IFITFAILS, Literal: "if it fails", Line: line, Column: column}
+				}
+			case "value":
+				if l.peekKeyword("for") { // 'value' alone stays a name
+					l.readNextWord()
+					return token.Token{Type: token.VALUEFOR, Literal: "value for", Line: line, Column: column}
+				}
+			case "has":
+				if l.peekKeyword("key") {
+					l.readNextWord()
+					return token.Token{Type: token.HASKEY, Literal: "has key", Line: line, Column: column}
+				}
+			case "at":
+				if l.peekKeyword("index") { // 'at' alone stays a name
+					l.readNextWord()
+					return token.Token{Type: token.ATINDEX, Literal: "at index", Line: line, Column: column}
+				}
+			case "item":
+				if l.peekKeyword("at", "index") { // 'item' alone stays a name
+					l.readNextWord()
+					l.readNextWord()
+					return token.Token{Type: token.ITEMATINDEX, Literal: "item at index", Line: line, Column: column}
 				}
-				return token.Token{Type: token.END, Literal: "end", Line: l.line, Column: l.column - len("end") + 1} // Just "end"
 			case "get":
 				if l.peekKeyword("item") {
-					l.readIdentifier()
+					l.readNextWord()
 					if l.peekKeyword("at") {
-						l.readIdentifier()
+						l.readNextWord()
 						if l.peekKeyword("index") {
-							l.readIdentifier()
-							return token.Token{Type: token.GETITEMATINDEX, Literal: "get item at index", Line: l.line, Column: l.column - len("get item at index") + 1}
+							l.readNextWord()
+							return token.Token{Type: token.GETITEMATINDEX, Literal: "get item at index", Line: line, Column: column}
 						}
 					}
 				}
-				return token.Token{Type: token.GETITEMATINDEX, Literal: "get", Line: l.line, Column: l.column - len("get") + 1} // Just "get" - might need refinement
+				return token.Token{Type: token.GETITEMATINDEX, Literal: "get", Line: line, Column: column} // Just "get" - might need refinement
+			case "stop":
+				if l.peekKeyword("loop") {
+					l.readNextWord()
+					return token.Token{Type: token.STOP, Literal: "stop loop", Line: line, Column: column}
+				}
+			case "skip":
+				if l.peekKeyword("to") {
+					l.readNextWord()
+					if l.peekKeyword("next") {
+						l.readNextWord()
+						return token.Token{Type: token.SKIP, Literal: "skip to next", Line: line, Column: column}
+					}
+					return token.Token{Type: token.SKIP, Literal: "skip to", Line: line, Column: column} // Parsed as 'skip'; 'to' alone is not meaningful here
+				}
 			case "is":
 				if l.peekKeyword("defined") {
-					l.readIdentifier()
-					return token.Token{Type: token.ISDEFINED, Literal: "is defined", Line: l.line, Column: l.column - len("is defined") + 1}
+					l.readNextWord()
+					return token.Token{Type: token.ISDEFINED, Literal: "is defined", Line: line, Column: column}
 				}
-				return token.Token{Type: token.ISDEFINED, Literal: "is", Line: l.line, Column: l.column - len("is") + 1} // Just "is" - might need refinement
+				return token.Token{Type: token.IS, Literal: "is", Line: line, Column: column} // An arm of 'when'
 			case "convert":
 				if l.peekKeyword("to") {
-					l.readIdentifier()
+					l.readNextWord()
 					if l.peekKeyword("number") {
-						l.readIdentifier()
-						return token.Token{Type: token.CONVERTTONUMBER, Literal: "convert to number", Line: l.line, Column: l.column - len("convert to number") + 1}
+						l.readNextWord()
+						return token.Token{Type: token.CONVERTTONUMBER, Literal: "convert to number", Line: line, Column: column}
 					} else if l.peekKeyword("string") {
-						l.readIdentifier()
-						return token.Token{Type: token.CONVERTTOSTRING, Literal: "convert to string", Line: l.line, Column: l.column - len("convert to string") + 1}
+						l.readNextWord()
+						return token.Token{Type: token.CONVERTTOSTRING, Literal: "convert to string", Line: line, Column: column}
 					}
 				}
-				return token.Token{Type: token.CONVERTTONUMBER, Literal: "convert", Line: l.line, Column: l.column - len("convert") + 1} // Just "convert" - might need refinement
+				return token.Token{Type: token.CONVERTTONUMBER, Literal: "convert", Line: line, Column: column} // Just "convert" - might need refinement
 			}
 
 
 			tokType := token.LookupIdent(ident)
-			return token.Token{Type: tokType, Literal: ident, Line: l.line, Column: l.column - len(ident) + 1}
-		} else if unicode.IsDigit(rune(l.ch)) {
+			return token.Token{Type: tokType, Literal: ident, Line: line, Column: column}
+		} else if isDigit(l.ch) {
 			return l.readNumber()
+		} else if l.atInvalidRune() {
+			tok = token.Token{Type: token.ILLEGAL, Literal: l.input[l.position:l.readPosition], Line: line, Column: column}
+			l.addError(tok, diagnostic.CodeIllegalCharacter, "invalid UTF-8 byte %#x", l.input[l.position])
 		} else {
 			tok = newToken(token.ILLEGAL, l.ch)
-			tok.Line = l.line
-			tok.Column = l.column
+			tok.Line = line
+			tok.Column = column
+			l.addError(tok, diagnostic.CodeIllegalCharacter, "illegal character %q", tok.Literal)
 		}
 	}
 
@@ -153,44 +253,82 @@ func (l *Lexer) NextToken() token.Token {
 	return tok
 }
 
-func (l *Lexer) peekKeyword(keyword string) bool {
+// peekKeyword reports whether the next words are keywords, in order, without
+// consuming them. The words must be on the current line, so that 'end' at the
+// end of one line and 'for' at the start of the next stay separate tokens.
+func (l *Lexer) peekKeyword(keywords ...string) bool {
 	currentPos := l.position
 	currentReadPos := l.readPosition
+	currentLine := l.line
 	currentColumn := l.column
 	currentChar := l.ch
 
-	l.skipWhitespace() // Skip any whitespace before the potential keyword
+	matched := true
+	for _, keyword := range keywords {
+		l.skipSpaces() // Skip the blanks before the potential keyword, but not a newline
 
-	startPos := l.position
-	for unicode.IsLetter(rune(l.ch)) {
-		l.readChar()
+		startPos := l.position
+		for isLetter(l.ch) {
+			l.readChar()
+		}
+		if l.input[startPos:l.position] != keyword {
+			matched = false
+			break
+		}
 	}
-	peekedWord := l.input[startPos:l.position]
 
 	l.position = currentPos
 	l.readPosition = currentReadPos
+	l.line = currentLine
 	l.column = currentColumn
 	l.ch = currentChar // Restore lexer state
 
-	return peekedWord == keyword
+	return matched
 }
 
+// readNextWord consumes the word a successful peekKeyword looked at.
+func (l *Lexer) readNextWord() string {
+	l.skipSpaces()
+	return l.readIdentifier()
+}
 
 func (l *Lexer) readIdentifier() string {
 	startPos := l.position
-	for unicode.IsLetter(rune(l.ch)) || unicode.IsDigit(rune(l.ch)) || l.ch == '_' { // Removed space from identifier chars
+	for isIdentifierChar(l.ch) { // Removed space from identifier chars
 		l.readChar()
 	}
 	return l.input[startPos:l.position]
 }
 
+// isLetter reports whether ch can start an identifier. Any Unicode letter
+// qualifies, so learners can name things in their own language.
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch)
+}
+
+// isIdentifierChar reports whether ch can continue an identifier: letters,
+// digits of any script, underscores and the combining marks that scripts
+// such as Devanagari attach to letters.
+func isIdentifierChar(ch rune) bool {
+	return isLetter(ch) || unicode.IsDigit(ch) || unicode.IsMark(ch) || ch == '_'
+}
+
+// isDigit reports whether ch is an ASCII digit. Numeric literals are written
+// with ASCII digits only.
+func isDigit(ch rune) bool {
+	return '0' <= ch && ch <= '9'
+}
+
 
 func (l *Lexer) skipWhitespace() {
-	for unicode.IsSpace(rune(l.ch)) {
-		if l.ch == '\n' {
-			l.line++
-			l.column = 0 // Reset column on newline
-		}
+	for unicode.IsSpace(l.ch) {
+		l.readChar()
+	}
+}
+
+// skipSpaces skips spaces and tabs, stopping at the end of the line.
+func (l *Lexer) skipSpaces() {
+	for l.ch == ' ' || l.ch == '\t' {
 		l.readChar()
 	}
 }
@@ -202,42 +340,176 @@ func (l *Lexer) skipWhitespace() {
 	// }
 	// literal := l.input[startPos:l.position]
 	// tokType := token.LookupIdent(literal)
-	// return token.Token{Type: tokType, Literal: literal, Line: l.line, Column: l.column - len(literal) + 1}
+	// return token.Token{Type: tokType, Literal: literal, Line: line, Column: column}
 // }
 
+// readNumber reads an integer or float literal: decimal digits with an
+// optional fraction and exponent, or an integer with a 0x or 0b prefix. Digits
+// may be grouped with underscores. A malformed number is reported and
+// returned as a single ILLEGAL token.
 func (l *Lexer) readNumber() token.Token {
-    startPos := l.position
-    for unicode.IsDigit(rune(l.ch)) || l.ch == '.' {
-        l.readChar()
-    }
-    return token.Token{Type: token.NUMBER, Literal: l.input[startPos:l.position], Line: l.line, Column: l.column - len(l.input[startPos:l.position]) + 1}
+	tok := token.Token{Type: token.INT, Line: l.line, Column: l.column}
+	startPos := l.position
+	problem := ""
+
+	if base := prefixedBase(l.ch, l.peekChar()); base != "" {
+		l.readChar()
+		l.readChar() // Move past the prefix
+		valid := isHexDigit
+		if base == "binary" {
+			valid = isBinaryDigit
+		}
+		digitsPos := l.position
+		problem = l.readDigits(valid)
+		if problem == "" && l.position == digitsPos {
+			problem = "missing digits after " + l.input[startPos:digitsPos]
+		} else if problem == "" && isHexDigit(l.ch) {
+			problem = fmt.Sprintf("invalid digit %q in %s literal", l.ch, base)
+		}
+	} else {
+		problem = l.readDigits(isDigit)
+		if problem == "" && l.ch == '.' && isDigit(l.peekChar()) {
+			tok.Type = token.FLOAT
+			l.readChar()
+			problem = l.readDigits(isDigit)
+		}
+		if problem == "" && (l.ch == 'e' || l.ch == 'E') {
+			tok.Type = token.FLOAT
+			l.readChar()
+			if l.ch == '+' || l.ch == '-' {
+				l.readChar()
+			}
+			if isDigit(l.ch) {
+				problem = l.readDigits(isDigit)
+			} else {
+				problem = "exponent has no digits"
+			}
+		}
+		if problem == "" && l.ch == '.' {
+			problem = "unexpected '.'"
+		}
+	}
+	if problem == "" && isIdentifierChar(l.ch) {
+		problem = fmt.Sprintf("unexpected %q", l.ch)
+	}
+
+	if problem != "" {
+		for isIdentifierChar(l.ch) || l.ch == '.' { // Take the rest of the word so it is reported once
+			l.readChar()
+		}
+		tok.Type = token.ILLEGAL
+		tok.Literal = l.input[startPos:l.position]
+		l.addError(tok, diagnostic.CodeMalformedNumber, "malformed number %q: %s", tok.Literal, problem)
+		return tok
+	}
+	tok.Literal = l.input[startPos:l.position]
+	return tok
 }
 
-func (l *Lexer) readString() token.Token {
-	startPos := l.position + 1 // Skip the opening quote
-	l.readChar() // Move past the opening quote
-	for l.ch != '"' && l.ch != 0 {
+// readDigits consumes digits accepted by valid, which may be separated by
+// single underscores. It returns a description of a misplaced underscore, or
+// "" if the digits are well formed.
+func (l *Lexer) readDigits(valid func(rune) bool) string {
+	for valid(l.ch) || l.ch == '_' {
+		if l.ch == '_' && !valid(l.peekChar()) {
+			l.readChar()
+			return "'_' must separate successive digits"
+		}
 		l.readChar()
 	}
-	literal := l.input[startPos:l.position]
-	return token.Token{Type: token.STRING, Literal: literal, Line: l.line, Column: l.column - len(literal) -1 } // Adjust column to start of string content
+	return ""
 }
 
-func (l *Lexer) readComment() token.Token {
-	startPos := l.position
+// prefixedBase returns the name of the base introduced by a 0x or 0b prefix,
+// or "" if ch and next do not start one.
+func prefixedBase(ch, next rune) string {
+	if ch != '0' {
+		return ""
+	}
+	switch next {
+	case 'x', 'X':
+		return "hexadecimal"
+	case 'b', 'B':
+		return "binary"
+	}
+	return ""
+}
+
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || ('a' <= ch && ch <= 'f') || ('A' <= ch && ch <= 'F')
+}
+
+func isBinaryDigit(ch rune) bool {
+	return ch == '0' || ch == '1'
+}
+
+// skipLineComment skips a '#' comment up to the end of the line.
+func (l *Lexer) skipLineComment() {
 	for l.ch != '\n' && l.ch != 0 {
 		l.readChar()
 	}
-	literal := l.input[startPos:l.position]
-	return token.Token{Type: token.COMMENT, Literal: literal, Line: l.line, Column: l.column - len(literal) + 1}
+}
+
+// readDocComment reads a '##' comment. The literal is the text after the
+// hashes, without the space that usually follows them.
+func (l *Lexer) readDocComment() token.Token {
+	tok := token.Token{Type: token.DOCCOMMENT, Line: l.line, Column: l.column}
+	l.readChar()
+	l.readChar() // Move past '##'
+	if l.ch == ' ' {
+		l.readChar()
+	}
+	startPos := l.position
+	l.skipLineComment()
+	tok.Literal = strings.TrimRight(l.input[startPos:l.position], " \t\r")
+	return tok
+}
+
+// skipBlockComment skips a '/* ... */' comment. Block comments nest, so a
+// commented-out region may itself contain block comments.
+func (l *Lexer) skipBlockComment() {
+	open := token.Token{Literal: "/*", Line: l.line, Column: l.column}
+	depth := 0
+	for {
+		switch {
+		case l.ch == 0:
+			l.addError(open, diagnostic.CodeUnterminatedComment, "unterminated block comment")
+			return
+		case l.ch == '/' && l.peekChar() == '*':
+			depth++
+			l.readChar()
+		case l.ch == '*' && l.peekChar() == '/':
+			depth--
+			l.readChar()
+			if depth == 0 {
+				l.readChar() // Move past the closing '/'
+				return
+			}
+		}
+		l.readChar()
+	}
 }
 
 
-func newToken(tokenType token.TokenType, ch byte) token.Token {
+func newToken(tokenType token.TokenType, ch rune) token.Token {
 	return token.Token{Type: tokenType, Literal: string(ch)}
 }
 
-// Errors returns the list of lexer errors.
+// addError records a diagnostic spanning tok.
+func (l *Lexer) addError(tok token.Token, code string, format string, a ...interface{}) {
+	l.errors = append(l.errors, diagnostic.New(code, diagnostic.TokenSpan(tok), format, a...))
+}
+
+// Errors returns the list of lexer errors as messages with their positions.
 func (l *Lexer) Errors() []string {
+	msgs := make([]string, len(l.errors))
+	for i, d := range l.errors {
+		msgs[i] = fmt.Sprintf("%s at line %d, column %d", d.Message, d.Span.Start.Line, d.Span.Start.Column)
+	}
+	return msgs
+}
+
+// Diagnostics returns the lexer errors as diagnostics with source spans.
+func (l *Lexer) Diagnostics() []*diagnostic.Diagnostic {
 	return l.errors
 }
@@ -0,0 +1,196 @@
+package lexer
+
+import (
+	"testing"
+	"wordlang/diagnostic"
+	"wordlang/token"
+)
+
+// lexAll returns every token of input up to, but not including, EOF.
+func lexAll(input string) ([]token.Token, *Lexer) {
+	l := New(input)
+	var toks []token.Token
+	for {
+		tok := l.NextToken()
+		if tok.Type == token.EOF {
+			return toks, l
+		}
+		toks = append(toks, tok)
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	tests := []struct {
+		input   string
+		typ     token.TokenType
+		literal string
+	}{
+		{"0", token.INT, "0"},
+		{"42", token.INT, "42"},
+		{"1_000_000", token.INT, "1_000_000"},
+		{"0x1F", token.INT, "0x1F"},
+		{"0Xff", token.INT, "0Xff"},
+		{"0b1010", token.INT, "0b1010"},
+		{"0b1_0", token.INT, "0b1_0"},
+		{"3.14", token.FLOAT, "3.14"},
+		{"1_0.2_5", token.FLOAT, "1_0.2_5"},
+		{"1e10", token.FLOAT, "1e10"},
+		{"2.5E-3", token.FLOAT, "2.5E-3"},
+		{"6e+2", token.FLOAT, "6e+2"},
+	}
+
+	for _, tt := range tests {
+		toks, l := lexAll(tt.input)
+		if len(l.Errors()) != 0 {
+			t.Errorf("%q: unexpected errors %v", tt.input, l.Errors())
+			continue
+		}
+		if len(toks) != 1 || toks[0].Type != tt.typ || toks[0].Literal != tt.literal {
+			t.Errorf("%q: got %v, want one %s %q", tt.input, toks, tt.typ, tt.literal)
+		}
+	}
+}
+
+func TestMalformedNumbers(t *testing.T) {
+	tests := []struct {
+		input   string
+		literal string // The single ILLEGAL token
+	}{
+		{"1__0", "1__0"},
+		{"10_", "10_"},
+		{"1e", "1e"},
+		{"1e+", "1e+"},
+		{"1.2.3", "1.2.3"},
+		{"0x", "0x"},
+		{"0xG1", "0xG1"},
+		{"0b102", "0b102"},
+		{"12abc", "12abc"},
+	}
+
+	for _, tt := range tests {
+		toks, l := lexAll(tt.input)
+		if len(toks) == 0 || toks[0].Type != token.ILLEGAL || toks[0].Literal != tt.literal {
+			t.Errorf("%q: got %v, want ILLEGAL %q first", tt.input, toks, tt.literal)
+			continue
+		}
+		diags := l.Diagnostics()
+		if len(diags) != 1 || diags[0].Code != diagnostic.CodeMalformedNumber {
+			t.Errorf("%q: got diagnostics %v, want one %s", tt.input, l.Errors(), diagnostic.CodeMalformedNumber)
+		}
+	}
+}
+
+func TestNumberFollowedByText(t *testing.T) {
+	toks, l := lexAll("1.")
+	if len(l.Errors()) != 1 {
+		t.Fatalf("'1.': got errors %v, want one", l.Errors())
+	}
+	if toks[0].Type != token.ILLEGAL {
+		t.Errorf("'1.': got %v", toks)
+	}
+
+	toks, l = lexAll("add 1 and -2")
+	want := []token.TokenType{token.ADD, token.INT, token.AND, token.MINUS, token.INT}
+	if len(l.Errors()) != 0 || len(toks) != len(want) {
+		t.Fatalf("got %v, errors %v", toks, l.Errors())
+	}
+	for i, typ := range want {
+		if toks[i].Type != typ {
+			t.Errorf("token %d: got %s, want %s", i, toks[i].Type, typ)
+		}
+	}
+}
+
+func TestMultiWordKeywords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []token.TokenType
+	}{
+		{"greater than", []token.TokenType{token.GREATERTHAN}},
+		{"greater or equal", []token.TokenType{token.GREATEREQUAL}},
+		{"less  than", []token.TokenType{token.LESSTHAN}},
+		{"less or equal", []token.TokenType{token.LESSEQUAL}},
+		{"end if", []token.TokenType{token.ENDIF}},
+		{"end while", []token.TokenType{token.ENDWHILE}},
+		{"end foreach", []token.TokenType{token.ENDFOREACH}},
+		{"end function", []token.TokenType{token.ENDFUNCTION}},
+		{"end group", []token.TokenType{token.ENDGROUP}},
+		{"end repeat", []token.TokenType{token.ENDREPEAT}},
+		{"end for", []token.TokenType{token.ENDFOR}},
+		{"end when", []token.TokenType{token.ENDWHEN}},
+		{"end try", []token.TokenType{token.ENDTRY}},
+		{"end", []token.TokenType{token.END}},
+		{"get item at index", []token.TokenType{token.GETITEMATINDEX}},
+		{"item at index", []token.TokenType{token.ITEMATINDEX}},
+		{"item at", []token.TokenType{token.IDENT, token.IDENT}},
+		{"at index", []token.TokenType{token.ATINDEX}},
+		{"value for", []token.TokenType{token.VALUEFOR}},
+		{"value", []token.TokenType{token.IDENT}},
+		{"has key", []token.TokenType{token.HASKEY}},
+		{"if it fails", []token.TokenType{token.IFITFAILS}},
+		{"if it", []token.TokenType{token.IF, token.IDENT}},
+		{"stop loop", []token.TokenType{token.STOP}},
+		{"skip to next", []token.TokenType{token.SKIP}},
+		{"is defined", []token.TokenType{token.ISDEFINED}},
+		{"is", []token.TokenType{token.IS}},
+		{"convert to number", []token.TokenType{token.CONVERTTONUMBER}},
+		{"convert to string", []token.TokenType{token.CONVERTTOSTRING}},
+	}
+
+	for _, tt := range tests {
+		toks, l := lexAll(tt.input)
+		if len(l.Errors()) != 0 {
+			t.Errorf("%q: unexpected errors %v", tt.input, l.Errors())
+			continue
+		}
+		if len(toks) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.input, toks, tt.want)
+			continue
+		}
+		for i, typ := range tt.want {
+			if toks[i].Type != typ {
+				t.Errorf("%q: token %d is %s, want %s", tt.input, i, toks[i].Type, typ)
+			}
+		}
+	}
+}
+
+// TestMultiWordKeywordsStayOnOneLine checks that words on separate lines are
+// not combined: a function closed with a bare 'end' may be followed by a loop.
+func TestMultiWordKeywordsStayOnOneLine(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []token.TokenType
+	}{
+		{"end\nfor", []token.TokenType{token.END, token.FOR}},
+		{"end\nrepeat", []token.TokenType{token.END, token.REPEAT}},
+		{"end \n  while", []token.TokenType{token.END, token.WHILE}},
+		{"greater\nthan", []token.TokenType{token.GREATERTHAN, token.IDENT}},
+		{"end\tfor", []token.TokenType{token.ENDFOR}},
+		{"end\nwhen x", []token.TokenType{token.END, token.WHEN, token.IDENT}},
+		{"end\ntry", []token.TokenType{token.END, token.TRY}},
+		{"value\nfor", []token.TokenType{token.IDENT, token.FOR}},
+		{"has\nkey", []token.TokenType{token.IDENT, token.IDENT}},
+	}
+
+	for _, tt := range tests {
+		toks, _ := lexAll(tt.input)
+		if len(toks) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.input, toks, tt.want)
+			continue
+		}
+		for i, typ := range tt.want {
+			if toks[i].Type != typ {
+				t.Errorf("%q: token %d is %s, want %s", tt.input, i, toks[i].Type, typ)
+			}
+		}
+	}
+}
+
+func TestMultiWordKeywordPosition(t *testing.T) {
+	toks, _ := lexAll("print 1\n  end  while")
+	last := toks[len(toks)-1]
+	if last.Type != token.ENDWHILE || last.Line != 2 || last.Column != 3 {
+		t.Errorf("got %s at %d:%d, want ENDWHILE at 2:3", last.Type, last.Line, last.Column)
+	}
+}
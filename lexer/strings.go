@@ -0,0 +1,188 @@
+package lexer
+
+import (
+	"strings"
+	"unicode/utf8"
+	"wordlang/diagnostic"
+	"wordlang/token"
+)
+
+// escapes maps the character after a backslash to the character it stands for.
+var escapes = map[rune]rune{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'"':  '"',
+	'\\': '\\',
+	'{':  '{',
+	'}':  '}',
+}
+
+// StringPart is one piece of an interpolated string: either literal text, with
+// escapes already processed, or the source of an embedded expression.
+type StringPart struct {
+	Text   string
+	IsExpr bool
+	Line   int // Position of Text in the source, for expressions
+	Column int
+}
+
+// readString reads a string literal, processing escape sequences. The token is
+// positioned at the opening quote and the lexer stops on the closing quote.
+//
+// A string that contains an unescaped '{' becomes an INTERPOLATED token whose
+// literal is the raw text between the quotes; SplitInterpolation breaks it up.
+func (l *Lexer) readString() token.Token {
+	tok := token.Token{Type: token.STRING, Line: l.line, Column: l.column}
+	var value strings.Builder
+	startPos := l.position + 1 // Skip the opening quote
+	l.readChar()               // Move past the opening quote
+
+	for l.ch != '"' {
+		switch l.ch {
+		case 0:
+			l.addError(tok, diagnostic.CodeUnterminatedString, "unterminated string literal")
+			return l.unterminatedString(tok, startPos)
+		case '\\':
+			escTok := token.Token{Literal: "\\", Line: l.line, Column: l.column}
+			l.readChar()
+			if r, ok := escapes[l.ch]; ok {
+				value.WriteRune(r)
+			} else if l.ch == 0 {
+				continue // reported as unterminated above
+			} else {
+				escTok.Literal += string(l.ch)
+				l.addError(escTok, diagnostic.CodeInvalidEscape, "invalid escape sequence %s in string", escTok.Literal)
+				value.WriteRune(l.ch)
+			}
+		case '{':
+			tok.Type = token.INTERPOLATED
+			if !l.skipInterpolation() {
+				l.addError(tok, diagnostic.CodeUnterminatedString, "unterminated string literal: '{' is never closed")
+				return l.unterminatedString(tok, startPos)
+			}
+		default:
+			value.WriteRune(l.ch)
+		}
+		l.readChar()
+	}
+
+	if tok.Type == token.INTERPOLATED {
+		tok.Literal = l.input[startPos:l.position]
+	} else {
+		tok.Literal = value.String()
+	}
+	return tok
+}
+
+// unterminatedString finishes a string that runs into the end of the input. It
+// becomes a plain string of the raw text, so an unclosed '{' is not parsed.
+func (l *Lexer) unterminatedString(tok token.Token, startPos int) token.Token {
+	tok.Type = token.STRING
+	tok.Literal = l.input[startPos:l.position]
+	return tok
+}
+
+// skipInterpolation moves from a '{' to its closing '}', stepping over any
+// string literals inside the expression. The expression itself is lexed later,
+// when the parser splits the string. It reports false if the input ends first.
+func (l *Lexer) skipInterpolation() bool {
+	l.readChar()
+	for l.ch != '}' {
+		switch l.ch {
+		case 0:
+			return false
+		case '"':
+			l.readChar()
+			for l.ch != '"' && l.ch != 0 {
+				if l.ch == '\\' {
+					l.readChar()
+				}
+				if l.ch != 0 {
+					l.readChar()
+				}
+			}
+			if l.ch == 0 {
+				return false
+			}
+		}
+		l.readChar()
+	}
+	return true
+}
+
+// SplitInterpolation breaks the literal of an INTERPOLATED token into its text
+// and expression parts. The lexer has already reported malformed input, so
+// invalid escapes are kept as they are here.
+func SplitInterpolation(tok token.Token) []StringPart {
+	var parts []StringPart
+	var text strings.Builder
+	raw := tok.Literal
+	line, column := tok.Line, tok.Column+1 // First character after the quote
+
+	advance := func(r rune) {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, StringPart{Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(raw); {
+		r, width := utf8.DecodeRuneInString(raw[i:])
+		switch r {
+		case '\\':
+			next, nextWidth := utf8.DecodeRuneInString(raw[i+width:])
+			if esc, ok := escapes[next]; ok {
+				text.WriteRune(esc)
+			} else {
+				text.WriteRune(next)
+			}
+			advance(r)
+			advance(next)
+			i += width + nextWidth
+			continue
+		case '{':
+			flush()
+			advance(r)
+			i += width
+			part := StringPart{IsExpr: true, Line: line, Column: column}
+			start := i
+			inString := false
+			for i < len(raw) {
+				c, w := utf8.DecodeRuneInString(raw[i:])
+				if !inString && c == '}' {
+					break
+				}
+				if c == '"' {
+					inString = !inString
+				} else if c == '\\' && inString {
+					advance(c)
+					i += w
+					c, w = utf8.DecodeRuneInString(raw[i:])
+				}
+				advance(c)
+				i += w
+			}
+			part.Text = raw[start:i]
+			parts = append(parts, part)
+			if i < len(raw) {
+				advance('}')
+				i++ // Skip the closing brace
+			}
+			continue
+		}
+		text.WriteRune(r)
+		advance(r)
+		i += width
+	}
+	flush()
+	return parts
+}
@@ -1,91 +1,204 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
-	"wordlang/interpreter"
+	"path/filepath"
+	"strings"
+	"wordlang/diagnostic"
+	"wordlang/engine"
 	"wordlang/lexer"
-	"wordlang/object"
-	"wordlang/parser"
+	"wordlang/token"
 )
 
+// Exit codes, so scripts and CI can tell failures apart.
+const (
+	exitOK           = 0
+	exitUsage        = 1 // Bad command line or unreadable file
+	exitParseError   = 2
+	exitRuntimeError = 3
+)
+
+const usage = `Usage: wordlang <command> [--format text|json] [file]
+
+Commands:
+  run <file>      run a program
+  repl            start the interactive interpreter
+  tokens <file>   print the token stream with line:column positions
+  ast <file>      print the syntax tree
+  check <file>    parse and validate a program without running it
+
+'wordlang <file>' is short for 'wordlang run <file>'.
+--format json writes diagnostics to stderr as a JSON array.
+Modules are searched next to the importing file, then in WORDLANG_PATH.`
+
 // main is the entry point of the WordLang interpreter.
-// It reads a file specified as a command-line argument, parses it,
-// and evaluates the resulting program.
+// It dispatches to a subcommand and exits with that command's status.
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: wordlang <filename>")
-		return
-	}
-
-	filename := os.Args[1]
-	runFile(filename)
+	os.Exit(runCommand(os.Args[1:]))
 }
 
-// runFile reads, parses, and evaluates a WordLang program from a file.
-func runFile(filename string) {
-	content, err := ioutil.ReadFile(filename)
-	if err != nil {
-		fmt.Printf("Error reading file: %s\n", err)
-		return
+// runCommand runs the subcommand named by args[0] and returns the exit code.
+func runCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return exitUsage
 	}
 
-	input := string(content)
-	l := lexer.New(input)
-	p := parser.New(l)
-	program := p.ParseProgram()
-
-	if len(p.Errors()) != 0 {
-		printParserErrors(p.Errors())
-		return
+	command, rest := args[0], args[1:]
+	switch command {
+	case "repl":
+		return repl()
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return exitOK
+	case "run", "tokens", "ast", "check":
+	default:
+		if strings.HasSuffix(command, ".wl") || strings.HasSuffix(command, ".wlang") {
+			command, rest = "run", args
+			break
+		}
+		fmt.Fprintf(os.Stderr, "wordlang: unknown command %q\n\n%s\n", command, usage)
+		return exitUsage
 	}
 
-	fmt.Println("\n--- AST ---")
-	fmt.Println(program.String())
-	fmt.Println("--- End AST ---\n")
+	flags := flag.NewFlagSet("wordlang "+command, flag.ContinueOnError)
+	format := flags.String("format", "text", "diagnostic output: text or json")
+	if err := flags.Parse(rest); err != nil {
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "wordlang %s: unknown format %q\n", command, *format)
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "wordlang %s: expected exactly one file\n", command)
+		return exitUsage
+	}
+	filename := flags.Arg(0)
 
-	env := interpreter.NewEnvironment()
-	result := interpreter.Eval(program, env)
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading file: %s\n", err)
+		return exitUsage
+	}
+	input := string(content)
+	r := reporter{json: *format == "json", file: filename, source: input}
 
-	if result != nil && result.Type() == object.ERROR_OBJ {
-		fmt.Println(result.Inspect())
+	switch command {
+	case "tokens":
+		return printTokens(r, input)
+	case "ast":
+		return printAST(r, input)
+	case "check":
+		return checkFile(r, input)
+	default:
+		return runFile(r, input)
 	}
 }
 
-// printParserErrors prints parser error messages to the console.
-func printParserErrors(errors []string) {
-	fmt.Println("Parser errors:")
-	for _, msg := range errors {
-		fmt.Println("\t" + msg)
+// runFile parses and evaluates a WordLang program attached to the process's streams.
+func runFile(r reporter, input string) int {
+	program, diagnostics := engine.Parse(r.file, input)
+	if len(diagnostics) != 0 {
+		r.report(diagnostics)
+		return exitParseError
 	}
-}
 
-// repl starts a Read-Eval-Print Loop for interactive WordLang execution.
-// This function is not called in main by default and is intended for testing.
-func repl() {
-	reader := bufio.NewReader(os.Stdin)
-	env := interpreter.NewEnvironment()
+	rt := engine.New(engine.Options{
+		Stdin:      os.Stdin,
+		Stdout:     os.Stdout,
+		Stderr:     os.Stderr,
+		File:       r.file,
+		SearchPath: filepath.SplitList(os.Getenv("WORDLANG_PATH")),
+	})
+	_, err := rt.Exec(context.Background(), program)
+	var runtimeErr *engine.RuntimeError
+	var exitErr *engine.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return exitErr.Code // The program chose its own status with 'exit'
+	case errors.As(err, &runtimeErr):
+		r.report(runtimeErr.Diagnostics())
+		return exitRuntimeError
+	}
+	return exitOK
+}
 
+// printTokens dumps the lexer's token stream, one token per line.
+func printTokens(r reporter, input string) int {
+	l := lexer.New(input)
 	for {
-		fmt.Print("WordLang > ")
-		line, _ := reader.ReadString('\n')
-		if line == "exit\n" {
+		tok := l.NextToken()
+		fmt.Printf("%d:%d\t%s\t%q\n", tok.Line, tok.Column, tok.Type, tok.Literal)
+		if tok.Type == token.EOF {
 			break
 		}
-		l := lexer.New(line)
-		p := parser.New(l)
-		program := p.ParseProgram()
+	}
 
-		if len(p.Errors()) != 0 {
-			printParserErrors(p.Errors())
-			continue
+	if len(l.Diagnostics()) != 0 {
+		for _, d := range l.Diagnostics() {
+			d.File = r.file
 		}
+		r.report(l.Diagnostics())
+		return exitParseError
+	}
+	return exitOK
+}
+
+// printAST prints the syntax tree, one top-level statement per line.
+func printAST(r reporter, input string) int {
+	program, diagnostics := engine.Parse(r.file, input)
+	for _, stmt := range program.Statements {
+		fmt.Println(stmt.String())
+	}
+
+	if len(diagnostics) != 0 {
+		r.report(diagnostics)
+		return exitParseError
+	}
+	return exitOK
+}
+
+// checkFile parses a program and reports its errors without running it.
+func checkFile(r reporter, input string) int {
+	_, diagnostics := engine.Parse(r.file, input)
+	if r.json {
+		r.report(diagnostics) // Always emit the array, even when it is empty
+	} else if len(diagnostics) == 0 {
+		fmt.Printf("%s: ok\n", r.file)
+	} else {
+		r.report(diagnostics)
+	}
+
+	if len(diagnostics) != 0 {
+		return exitParseError
+	}
+	return exitOK
+}
+
+// reporter prints diagnostics to stderr, as text with source snippets or as JSON.
+type reporter struct {
+	json   bool
+	file   string // The file being processed
+	source string // Its contents, for snippets
+}
+
+func (r reporter) report(diagnostics []*diagnostic.Diagnostic) {
+	if r.json {
+		diagnostic.RenderJSON(os.Stderr, diagnostics)
+		return
+	}
 
-		result := interpreter.Eval(program, env)
-		if result != nil {
-			fmt.Println(result.Inspect())
+	for _, d := range diagnostics {
+		source := r.source
+		if d.File != r.file { // Reported in an imported module
+			content, _ := os.ReadFile(d.File)
+			source = string(content)
 		}
+		diagnostic.Render(os.Stderr, d, source)
 	}
 }
@@ -0,0 +1,78 @@
+package object
+
+import "sort"
+
+// Environment holds variable bindings.
+type Environment struct {
+	store   map[string]Object
+	outer   *Environment // Enclosing scope, nil for the top-level scope of a module
+	session *Session     // Shared by every environment of one run, including imported modules
+	file    string       // Source file of the module this scope belongs to, "" if unknown
+}
+
+// NewEnvironment creates a new top-level environment with a fresh session.
+func NewEnvironment() *Environment {
+	return NewSession().NewEnvironment("")
+}
+
+// NewEnclosedEnvironment creates a new environment enclosed by outer environment.
+func NewEnclosedEnvironment(outer *Environment) *Environment {
+	env := outer.session.NewEnvironment(outer.file)
+	env.outer = outer
+	return env
+}
+
+// Get retrieves a variable from the environment, looking through the enclosing scopes.
+func (e *Environment) Get(name string) (Object, bool) {
+	obj, ok := e.store[name]
+	if !ok && e.outer != nil {
+		obj, ok = e.outer.Get(name)
+	}
+	return obj, ok
+}
+
+// Set sets a variable in the environment.
+func (e *Environment) Set(name string, val Object) Object {
+	e.store[name] = val
+	return val
+}
+
+// Assign updates name in the scope that defines it, looking through the
+// enclosing scopes. Unlike Set it never creates a binding; it reports false
+// if name is not defined.
+func (e *Environment) Assign(name string, val Object) bool {
+	for env := e; env != nil; env = env.outer {
+		if _, ok := env.store[name]; ok {
+			env.store[name] = val
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names bound directly in this environment, sorted.
+// Bindings of enclosing scopes are not included.
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.store))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Session returns the session this environment belongs to.
+func (e *Environment) Session() *Session {
+	return e.session
+}
+
+// File returns the source file of the module this environment belongs to.
+func (e *Environment) File() string {
+	return e.file
+}
+
+// SetFile records the source file of a top-level environment, so that
+// its imports are resolved relative to it.
+func (e *Environment) SetFile(path string) {
+	e.file = path
+}
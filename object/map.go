@@ -0,0 +1,106 @@
+package object
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// HashKey identifies a dictionary key by its type and value, so that two
+// strings with the same text find the same entry.
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+}
+
+// Hashable is implemented by the values that can be dictionary keys:
+// strings, integers and booleans.
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+	if b.Value {
+		value = 1
+	}
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+func (s *String) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(s.Value))
+	return HashKey{Type: s.Type(), Value: h.Sum64()}
+}
+
+// MapPair is one entry of a Map. The key is kept for printing and iteration.
+type MapPair struct {
+	Key   Hashable
+	Value Object
+}
+
+// Map object: a dictionary that remembers the order its keys were added in.
+// Like lists, dictionaries are shared and changed in place.
+type Map struct {
+	Pairs map[HashKey]MapPair
+	Order []HashKey // Keys in insertion order
+}
+
+// NewMap creates an empty dictionary.
+func NewMap() *Map {
+	return &Map{Pairs: map[HashKey]MapPair{}}
+}
+
+func (m *Map) Type() ObjectType { return MAP_OBJ }
+func (m *Map) Inspect() string {
+	pairs := make([]string, len(m.Order))
+	for i, hk := range m.Order {
+		pair := m.Pairs[hk]
+		pairs[i] = pair.Key.Inspect() + ": " + pair.Value.Inspect()
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// Get returns the value stored under key.
+func (m *Map) Get(key Hashable) (Object, bool) {
+	pair, ok := m.Pairs[key.HashKey()]
+	return pair.Value, ok
+}
+
+// Set stores value under key. A new key goes last; an existing one keeps its place.
+func (m *Map) Set(key Hashable, value Object) {
+	hk := key.HashKey()
+	if _, ok := m.Pairs[hk]; !ok {
+		m.Order = append(m.Order, hk)
+	}
+	m.Pairs[hk] = MapPair{Key: key, Value: value}
+}
+
+// Delete removes key and reports whether it was there.
+func (m *Map) Delete(key Hashable) bool {
+	hk := key.HashKey()
+	if _, ok := m.Pairs[hk]; !ok {
+		return false
+	}
+	delete(m.Pairs, hk)
+	for i, k := range m.Order {
+		if k == hk {
+			m.Order = append(m.Order[:i], m.Order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Keys returns the keys in insertion order.
+func (m *Map) Keys() []Object {
+	keys := make([]Object, len(m.Order))
+	for i, hk := range m.Order {
+		keys[i] = m.Pairs[hk].Key
+	}
+	return keys
+}
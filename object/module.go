@@ -0,0 +1,51 @@
+package object
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+)
+
+// Module is a loaded module: a WordLang file or a built-in Go module.
+// The bindings of its top-level environment are what other modules can import.
+type Module struct {
+	Name string
+	Path string // Resolved file path, empty for built-in modules
+	Env  *Environment
+}
+
+// Session holds the state shared by a program and every module it imports.
+type Session struct {
+	SearchPath []string           // Directories searched for .wl modules after the importing file's own
+	Modules    map[string]*Module // Loaded modules, keyed by absolute path or built-in name
+	Loading    []string           // Keys of the modules being loaded, innermost last
+
+	// The streams 'print', 'input' and the stdio builtins use. Stdin is
+	// buffered once per session so that no input is lost between reads.
+	Stdin  *bufio.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Context cancels a running program; loops and calls check it.
+	Context context.Context
+}
+
+// stdin buffers the process's standard input for every session reading it.
+var stdin = bufio.NewReader(os.Stdin)
+
+// NewSession creates an empty session attached to the process's standard streams.
+func NewSession() *Session {
+	return &Session{
+		Modules: make(map[string]*Module),
+		Stdin:   stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+		Context: context.Background(),
+	}
+}
+
+// NewEnvironment creates a top-level environment in this session for the given source file.
+func (s *Session) NewEnvironment(file string) *Environment {
+	return &Environment{store: make(map[string]Object), session: s, file: file}
+}
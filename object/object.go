@@ -3,6 +3,8 @@ package object
 import (
 	"fmt"
 	"strings"
+	"wordlang/ast"
+	"wordlang/diagnostic"
 )
 
 // ObjectType is a string representation of an object's type.
@@ -22,8 +24,14 @@ const (
 	STRING_OBJ       = "STRING"
 	NULL_OBJ         = "NULL"
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
+	EXIT_OBJ         = "EXIT"
+	BREAK_OBJ        = "BREAK"
+	CONTINUE_OBJ     = "CONTINUE"
 	ERROR_OBJ        = "ERROR"
 	LIST_OBJ         = "LIST"
+	FUNCTION_OBJ     = "FUNCTION"
+	BUILTIN_OBJ      = "BUILTIN"
+	MAP_OBJ          = "DICTIONARY"
 )
 
 // Integer object.
@@ -72,22 +80,135 @@ type ReturnValue struct {
 func (rv *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
 func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
+// Exit object: the signal an 'exit' statement sends. Like ReturnValue it
+// unwinds the evaluation, but through function calls as well, up to whoever
+// started the program. Only the CLI turns Code into a process exit status.
+type Exit struct {
+	Code int
+}
+
+func (e *Exit) Type() ObjectType { return EXIT_OBJ }
+func (e *Exit) Inspect() string  { return fmt.Sprintf("exit %d", e.Code) }
+
+// Break object: the signal a 'stop' statement sends to the loop it leaves.
+// Label is empty for the innermost loop.
+type Break struct {
+	Label string
+}
+
+func (b *Break) Type() ObjectType { return BREAK_OBJ }
+func (b *Break) Inspect() string  { return "stop" }
+
+// Continue object: the signal a 'skip' statement sends to the loop it
+// continues. Label is empty for the innermost loop.
+type Continue struct {
+	Label string
+}
+
+func (c *Continue) Type() ObjectType { return CONTINUE_OBJ }
+func (c *Continue) Inspect() string  { return "skip" }
+
 // Error object.
 type Error struct {
+	Code    string // A diagnostic code, e.g. diagnostic.CodeUndefinedName
 	Message string
+	Span    diagnostic.Span          // Where it happened; zero until the evaluator locates it
+	File    string                   // The source file Span is in, "" if unknown
+	Stack   []Frame                  // WordLang calls it unwound through, innermost first
+	Causes  []*diagnostic.Diagnostic // The errors behind it, e.g. an imported module's syntax errors
+}
+
+// Frame is a WordLang function call on an error's call stack.
+type Frame struct {
+	Function string              // Empty for anonymous functions
+	File     string              // The file of the call site, "" if unknown
+	Call     diagnostic.Position // The call site; zero for calls made by the host
+}
+
+// String describes the frame for a traceback.
+func (f Frame) String() string {
+	name := "anonymous function"
+	if f.Function != "" {
+		name = "function " + f.Function
+	}
+	if f.Call.Line == 0 {
+		return "in " + name
+	}
+	file := f.File
+	if file == "" {
+		file = "<input>"
+	}
+	return fmt.Sprintf("in %s, called at %s:%d:%d", name, file, f.Call.Line, f.Call.Column)
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
 func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
 
-// NewError creates a new Error object.
+// Diagnostic converts the error into a diagnostic. file is used when the error
+// does not know its own file. The call stack becomes a traceback in the notes,
+// innermost call first.
+func (e *Error) Diagnostic(file string) *diagnostic.Diagnostic {
+	d := diagnostic.New(e.Code, e.Span, "%s", e.Message)
+	d.File = file
+	if e.File != "" {
+		d.File = e.File
+	}
+	for _, frame := range e.Stack {
+		if frame.File == "" {
+			frame.File = file
+		}
+		d.Notes = append(d.Notes, frame.String())
+	}
+	return d
+}
+
+// Text is the message as a program sees it in 'if it fails as', without the
+// "Eval: " prefix the interpreter puts on its own errors.
+func (e *Error) Text() string {
+	return strings.TrimPrefix(e.Message, "Eval: ")
+}
+
+// Diagnostics is Diagnostic followed by the error's causes.
+func (e *Error) Diagnostics(file string) []*diagnostic.Diagnostic {
+	return append([]*diagnostic.Diagnostic{e.Diagnostic(file)}, e.Causes...)
+}
+
+// NewError creates a new Error object with the generic runtime error code.
 func NewError(format string, a ...interface{}) *Error {
-	return &Error{Message: fmt.Sprintf(format, a...)}
+	return NewCodedError(diagnostic.CodeRuntime, format, a...)
+}
+
+// NewCodedError creates a new Error object with a specific diagnostic code.
+func NewCodedError(code string, format string, a ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, a...)}
 }
 
-// List object.
+// List object. Lists are shared, not copied: every name bound to a list sees
+// the changes made to it in place.
 type List struct {
-	Elements []Object
+	Elements    []Object
+	ElementType ObjectType // The type every element has in a typed list, "" for 'list'
+}
+
+// typedListNames are the keywords that create typed lists, by element type.
+var typedListNames = map[ObjectType]string{
+	STRING_OBJ:  "strings",
+	INTEGER_OBJ: "numbers",
+	FLOAT_OBJ:   "decimals",
+}
+
+// Accept checks that value may be stored in the list and returns it as it is
+// stored: a decimals list keeps whole numbers as floats. A list without an
+// element type accepts anything.
+func (l *List) Accept(value Object) (Object, *Error) {
+	if l.ElementType == "" || value.Type() == l.ElementType {
+		return value, nil
+	}
+	if i, ok := value.(*Integer); ok && l.ElementType == FLOAT_OBJ {
+		return &Float{Value: float64(i.Value)}, nil
+	}
+	return nil, NewCodedError(diagnostic.CodeTypeMismatch, "Eval: a %s list can only hold %s values, got %s (%s)",
+		typedListNames[l.ElementType], l.ElementType, value.Type(), value.Inspect())
 }
 
 func (l *List) Type() ObjectType { return LIST_OBJ }
@@ -103,6 +224,41 @@ func (l *List) Inspect() string {
 	return out.String()
 }
 
+// Function object. Env is the environment the function was defined in (its closure).
+type Function struct {
+	Name       string // Empty for anonymous function literals
+	Parameters []*ast.Identifier
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
+func (f *Function) Inspect() string {
+	params := []string{}
+	for _, p := range f.Parameters {
+		params = append(params, p.String())
+	}
+	name := f.Name
+	if name == "" {
+		name = "anonymous"
+	}
+	return "function " + name + "(" + strings.Join(params, ", ") + ")"
+}
+
+// BuiltinFunction is the Go implementation behind a Builtin. env is the
+// caller's environment; builtins reach the session's streams through it.
+type BuiltinFunction func(env *Environment, args ...Object) Object
+
+// Builtin object: a function implemented in Go, provided by a built-in module.
+// It is called through the same path as WordLang functions; errors come back as *Error.
+type Builtin struct {
+	Module string
+	Name   string
+	Fn     BuiltinFunction
+}
+
+func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
+func (b *Builtin) Inspect() string  { return "builtin function " + b.Module + "." + b.Name }
 
 // Predefined boolean objects (for efficiency).
 var (
@@ -2,8 +2,12 @@ package parser
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"wordlang/ast"
+	"wordlang/diagnostic"
 	"wordlang/lexer"
 	"wordlang/token"
 )
@@ -14,7 +18,11 @@ type Parser struct {
 
 	curToken  token.Token
 	peekToken token.Token
-	errors    []string
+	curDoc    string // Doc comment written just before curToken
+	peekDoc   string
+	errors    []*diagnostic.Diagnostic
+	panicking bool // Set by an error, cleared once parsing resynchronizes
+	loops     []string // Labels of the loops around the current statement, innermost last; "" if unlabeled
 
 	prefixParseFns   map[token.TokenType]prefixParseFn
 	infixParseFns    map[token.TokenType]infixParseFn
@@ -35,7 +43,7 @@ func (p *Parser) registerStatement(tokenType token.TokenType, fn statementParseF
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{
 		l:                 l,
-		errors:            []string{},
+		errors:            []*diagnostic.Diagnostic{},
 		prefixParseFns:    make(map[token.TokenType]prefixParseFn),
 		infixParseFns:     make(map[token.TokenType]infixParseFn),
 		statementParseFns: make(map[token.TokenType]statementParseFn), // Initialize statementParseFns
@@ -52,13 +60,50 @@ func New(l *lexer.Lexer) *Parser {
 
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
-	p.peekToken = p.l.NextToken()
+	p.curDoc = p.peekDoc
+	p.peekToken, p.peekDoc = p.readToken()
+}
+
+// readToken reads the next token from the lexer. Doc comment lines in front of
+// it are joined and returned with it rather than parsed as tokens.
+func (p *Parser) readToken() (token.Token, string) {
+	var doc []string
+	tok := p.l.NextToken()
+	for tok.Type == token.DOCCOMMENT {
+		doc = append(doc, tok.Literal)
+		tok = p.l.NextToken()
+	}
+	return tok, strings.Join(doc, "\n")
+}
+
+// addError records a diagnostic spanning tok and enters panic mode. Errors
+// raised while panicking are follow-on noise from the first one and dropped.
+func (p *Parser) addError(tok token.Token, code string, format string, a ...interface{}) {
+	if p.panicking {
+		return
+	}
+	p.panicking = true
+	if tok.Type == token.EOF {
+		code = diagnostic.CodeUnexpectedEOF // Tells unfinished input, which the REPL reads more of, from wrong input
+	}
+	span := diagnostic.TokenSpan(tok)
+	if n := len(p.errors); n > 0 && p.errors[n-1].Span == span {
+		return // A wrong terminator is reported by its block, not again as unmatched
+	}
+	p.errors = append(p.errors, diagnostic.New(code, span, format, a...))
+}
+
+// addMisplacedError records a statement that parsed fine but is not allowed
+// where it appears. Parsing carries on normally, without panic mode.
+func (p *Parser) addMisplacedError(tok token.Token, format string, a ...interface{}) {
+	if p.panicking {
+		return
+	}
+	p.errors = append(p.errors, diagnostic.New(diagnostic.CodeMisplaced, diagnostic.TokenSpan(tok), format, a...))
 }
 
 func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead at line %d, column %d",
-		t, p.peekToken.Type, p.peekToken.Line, p.peekToken.Column)
-	p.errors = append(p.errors, msg)
+	p.addError(p.peekToken, diagnostic.CodeUnexpectedToken, "expected next token to be %s, got %s instead", t, p.peekToken.Type)
 }
 
 func (p *Parser) expectPeek(t token.TokenType) bool {
@@ -71,6 +116,30 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 	}
 }
 
+// expectHeaderEnd expects the word ending a block header ('then', 'do'). When the
+// header expression was broken and parsing stopped right on that word, it
+// resynchronizes there so that the block itself is still parsed and checked.
+func (p *Parser) expectHeaderEnd(t token.TokenType) bool {
+	if p.panicking && p.curTokenIs(t) {
+		p.panicking = false
+		return true
+	}
+	return p.expectPeek(t)
+}
+
+func (p *Parser) curError(t token.TokenType) {
+	p.addError(p.curToken, diagnostic.CodeUnexpectedToken, "expected %s, got %s instead", t, p.curToken.Type)
+}
+
+// expectCur checks the token a block stopped on, e.g. the 'endif' after parseBlockStatement.
+func (p *Parser) expectCur(t token.TokenType) bool {
+	if p.curTokenIs(t) {
+		return true
+	}
+	p.curError(t)
+	return false
+}
+
 func (p *Parser) curTokenIs(t token.TokenType) bool {
 	return p.curToken.Type == t
 }
@@ -85,15 +154,99 @@ func (p *Parser) ParseProgram() *ast.Program {
 	program.Statements = []ast.Statement{}
 
 	for !p.curTokenIs(token.EOF) {
-		stmt := p.parseStatement()
+		stmt := p.parseNextStatement()
 		if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
-		p.nextToken()
 	}
 
 	return program
 }
+
+// statementStarters are the keywords that begin a statement; after a syntax
+// error the parser skips ahead to one of them or to a block terminator.
+var statementStarters = map[token.TokenType]bool{
+	token.LET:      true,
+	token.IF:       true,
+	token.WHILE:    true,
+	token.FOREACH:  true,
+	token.FUNCTION: true,
+	token.PRINT:    true,
+	token.RETURN:   true,
+	token.EXIT:     true,
+	token.STOP:     true,
+	token.SKIP:     true,
+	token.REPEAT:   true,
+	token.FOR:      true,
+	token.INCREMENT: true,
+	token.DECREMENT: true,
+	token.SET:       true,
+	token.WHEN:      true,
+	token.TRY:       true,
+	token.RAISE:     true,
+	token.REMOVE:    true,
+	token.APPEND:    true,
+	token.INSERT:    true,
+}
+
+// parseNextStatement parses one statement and moves on to the next one,
+// resynchronizing first if the statement had a syntax error.
+func (p *Parser) parseNextStatement() ast.Statement {
+	start := p.curToken
+	stmt := p.parseStatement()
+
+	if p.panicking {
+		p.synchronize(start)
+	} else {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// synchronize leaves panic mode, skipping to the next statement keyword or block
+// terminator. If the error was found on such a token it stays there, unless that
+// token began the failed statement (so parsing always makes progress).
+func (p *Parser) synchronize(start token.Token) {
+	p.panicking = false
+
+	if p.curToken != start && (statementStarters[p.curToken.Type] || blockTerminators[p.curToken.Type]) {
+		return
+	}
+
+	p.nextToken()
+	for !statementStarters[p.curToken.Type] && !blockTerminators[p.curToken.Type] {
+		p.nextToken()
+	}
+}
+
+// skipBlock recovers from a syntax error in the header of the block begun by
+// start. It skips the rest of the header line and parses the body with
+// parseBody anyway, so that errors in the body are still found and the block's
+// closing word is not reported as having no matching block. Later parts such as
+// 'else' are skipped and parsed the same way, up to one of closers, where
+// curToken is left.
+func (p *Parser) skipBlock(start token.Token, parseBody func() *ast.BlockStatement, closers ...token.TokenType) {
+	if p.curToken != start && blockTerminators[p.curToken.Type] {
+		return // The header broke off on a terminator, which synchronize stays on
+	}
+
+	p.panicking = false
+	for {
+		for p.peekOnSameLine() && !p.peekTokenIs(token.EOF) {
+			p.nextToken()
+		}
+		parseBody()
+		if p.curTokenIs(token.EOF) || slices.Contains(closers, p.curToken.Type) {
+			return
+		}
+	}
+}
+
+// skipLoop is skipBlock for a loop, whose body may use 'stop' and 'skip'.
+func (p *Parser) skipLoop(start token.Token, label *ast.Identifier, closer token.TokenType) {
+	p.skipBlock(start, func() *ast.BlockStatement { return p.parseLoopBody(label) }, closer)
+}
+
 func (p *Parser) parseStatement() ast.Statement {
 	if parseStatementFn, ok := p.statementParseFns[p.curToken.Type]; ok {
 		return parseStatementFn() // Call the registered statement parser
@@ -130,23 +283,17 @@ func (p *Parser) parseStatement() ast.Statement {
 	}
 }*/
 
-func (p *Parser) parseLetStatement() *ast.LetStatement {
-	stmt := &ast.LetStatement{Token: p.curToken}
-
-	fmt.Println("parseLetStatement: curToken=", p.curToken, ", peekToken=", p.peekToken) // Debug print
+func (p *Parser) parseLetStatement() ast.Statement {
+	stmt := &ast.LetStatement{Token: p.curToken, Doc: p.curDoc}
 
 	if !p.expectPeek(token.IDENT) {
-		fmt.Println("parseLetStatement: expectPeek(IDENT) failed, peekToken=", p.peekToken) // Debug print
-		return nil // Error already added by expectPeek
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
 	}
 
 	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
-	fmt.Println("parseLetStatement: after IDENT, curToken=", p.curToken, ", peekToken=", p.peekToken) // Debug print
-
 	if !p.expectPeek(token.BE) { // Expect 'be' after variable name
-		fmt.Println("parseLetStatement: expectPeek(BE) failed, peekToken=", p.peekToken) // Debug print
-		return nil
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
 	}
 
 	p.nextToken() // Consume 'be', move to the expression
@@ -157,8 +304,140 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 	return stmt
 }
 
+// parseSetStatement parses 'set <name> to <expression>'.
+func (p *Parser) parseSetStatement() ast.Statement {
+	if p.peekTokenIs(token.VALUEFOR) {
+		return p.parseSetValueStatement()
+	}
+	if p.peekTokenIs(token.ITEMATINDEX) {
+		return p.parseSetItemStatement()
+	}
+
+	stmt := &ast.SetStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.TO) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	p.nextToken() // Consume 'to', move to the expression
+	stmt.Value = p.parseExpression(LOWEST)
+
+	return stmt
+}
+
+// parseSetValueStatement parses 'set value for <key> in <dictionary> to <expression>'.
+func (p *Parser) parseSetValueStatement() ast.Statement {
+	stmt := &ast.SetValueStatement{Token: p.curToken}
+
+	p.nextToken()
+	p.nextToken() // Consume 'value for', move to the key
+	stmt.Key = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.IN) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.Dictionary = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.TO) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+
+	return stmt
+}
+
+// parseSetItemStatement parses 'set item at index <index> of <list> to <expression>'.
+func (p *Parser) parseSetItemStatement() ast.Statement {
+	stmt := &ast.SetItemStatement{Token: p.curToken}
+
+	p.nextToken()
+	p.nextToken() // Consume 'item at index', move to the index
+	stmt.Index = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.OF) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.List = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.TO) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+
+	return stmt
+}
+
+// parseAppendStatement parses 'append <expression> to <list>'.
+func (p *Parser) parseAppendStatement() ast.Statement {
+	stmt := &ast.AppendStatement{Token: p.curToken}
+
+	p.nextToken() // Consume 'append'
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.TO) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.List = p.parseExpression(LOWEST)
 
-func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
+	return stmt
+}
+
+// parseInsertStatement parses 'insert <expression> at index <index> of <list>'.
+func (p *Parser) parseInsertStatement() ast.Statement {
+	stmt := &ast.InsertStatement{Token: p.curToken}
+
+	p.nextToken() // Consume 'insert'
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.ATINDEX) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.Index = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.OF) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.List = p.parseExpression(LOWEST)
+
+	return stmt
+}
+
+// parseRemoveStatement parses 'remove <value> from <expression>' and
+// 'remove item at index <index> from <list>'.
+func (p *Parser) parseRemoveStatement() ast.Statement {
+	stmt := &ast.RemoveStatement{Token: p.curToken}
+
+	if p.peekTokenIs(token.ITEMATINDEX) {
+		p.nextToken()
+		p.nextToken() // Consume 'item at index', move to the index
+		stmt.Index = p.parseExpression(LOWEST)
+	} else {
+		p.nextToken() // Consume 'remove'
+		stmt.Value = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(token.FROM) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.From = p.parseExpression(LOWEST)
+
+	return stmt
+}
+
+func (p *Parser) parseReturnStatement() ast.Statement {
 	stmt := &ast.ReturnStatement{Token: p.curToken}
 
 	p.nextToken() // Move past 'return'
@@ -182,30 +461,30 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 const (
 	_ int = iota
 	LOWEST
+	OR_PREC          // or
+	AND_PREC         // and
+	NOT_PREC         // not (binds looser than comparisons: 'not x greater than 5')
 	EQUALS_PREC      // equals, not equals
 	LESSGREATER_PREC // greater than, less than, etc.
-	SUM_PREC         // add, subtract
-	PRODUCT_PREC     // multiply, divide
-	PREFIX_PREC      // not
+	SUM_PREC         // operands of add, sub
+	PRODUCT_PREC     // operands of mult, div
+	PREFIX_PREC
 	CALL_PREC
 	INDEX_PREC
 )
 
+// precedence only lists tokens that continue an expression as infix operators.
+// The arithmetic verbs are prefix forms and delimit their own operands.
 var precedence = map[token.TokenType]int{
-	token.EQUALS:      EQUALS_PREC,
-	token.NOTEQUALS:   EQUALS_PREC,
-	token.GREATERTHAN: LESSGREATER_PREC,
-	token.LESSTHAN:    LESSGREATER_PREC,
-	token.GREATEREQUAL:LESSGREATER_PREC,
-	token.LESSEQUAL:   LESSGREATER_PREC,
-	token.ADD:         SUM_PREC,
-	token.SUBTRACT:    SUM_PREC,
-	token.MULTIPLY:    PRODUCT_PREC,
-	token.DIVIDE:      PRODUCT_PREC,
-	token.OR:          EQUALS_PREC, // Example precedence - adjust as needed
-	token.AND:         EQUALS_PREC, // Example precedence - adjust as needed
-	token.CALL:        CALL_PREC,
-	token.GETITEMATINDEX: INDEX_PREC, // Example precedence
+	token.OR:           OR_PREC,
+	token.AND:          AND_PREC,
+	token.EQUALS:       EQUALS_PREC,
+	token.NOTEQUALS:    EQUALS_PREC,
+	token.GREATERTHAN:  LESSGREATER_PREC,
+	token.LESSTHAN:     LESSGREATER_PREC,
+	token.GREATEREQUAL: LESSGREATER_PREC,
+	token.LESSEQUAL:    LESSGREATER_PREC,
+	token.HASKEY:       LESSGREATER_PREC,
 }
 
 
@@ -228,12 +507,19 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	prefixFn := p.prefixParseFns[p.curToken.Type]
 	if prefixFn == nil {
 		p.noPrefixParseFnError(p.curToken.Type)
-		return nil
+		return &ast.BadExpression{Token: p.curToken}
 	}
-	leftExp := prefixFn() // Just call the prefix function and return
+	leftExp := prefixFn()
 
-	// --- REMOVE ALL INFIX PARSING LOGIC ---
-	// No more infix loop or infix function calls here for now.
+	for precedence < p.peekPrecedence() {
+		infixFn := p.infixParseFns[p.peekToken.Type]
+		if infixFn == nil {
+			return leftExp
+		}
+
+		p.nextToken()
+		leftExp = infixFn(leftExp)
+	}
 
 	return leftExp
 }
@@ -252,8 +538,19 @@ func (p *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
 }
 
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found at line %d, column %d", t, p.curToken.Line, p.curToken.Column)
-	p.errors = append(p.errors, msg)
+	if t == token.ILLEGAL {
+		p.panicking = true // The lexer already reported the character
+		return
+	}
+	if t == token.EOF {
+		p.addError(p.curToken, diagnostic.CodeExpectedExpr, "expected an expression, got end of input")
+		return
+	}
+	if blockTerminators[t] {
+		p.addError(p.curToken, diagnostic.CodeUnexpectedToken, "unexpected %s without a matching block", t)
+		return
+	}
+	p.addError(p.curToken, diagnostic.CodeExpectedExpr, "expected an expression, got %s", t)
 }
 
 
@@ -264,11 +561,20 @@ func (p *Parser) parseIdentifier() ast.Expression {
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: p.curToken}
 
-	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
+	// The lexer has checked the digits; only the value can still be out of range.
+	digits, base := strings.ReplaceAll(p.curToken.Literal, "_", ""), 10
+	if len(digits) > 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X':
+			digits, base = digits[2:], 16
+		case 'b', 'B':
+			digits, base = digits[2:], 2
+		}
+	}
+	value, err := strconv.ParseInt(digits, base, 64)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer at line %d, column %d", p.curToken.Literal, p.curToken.Line, p.curToken.Column)
-		p.errors = append(p.errors, msg)
-		return nil
+		p.addError(p.curToken, diagnostic.CodeInvalidNumber, "integer %s is too large", p.curToken.Literal)
+		return &ast.BadExpression{Token: lit.Token, Partial: lit}
 	}
 
 	lit.Value = value
@@ -278,11 +584,10 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 func (p *Parser) parseFloatLiteral() ast.Expression {
 	lit := &ast.FloatLiteral{Token: p.curToken}
 
-	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	value, err := strconv.ParseFloat(strings.ReplaceAll(p.curToken.Literal, "_", ""), 64)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as float at line %d, column %d", p.curToken.Literal, p.curToken.Line, p.curToken.Column)
-		p.errors = append(p.errors, msg)
-		return nil
+		p.addError(p.curToken, diagnostic.CodeInvalidNumber, "decimal %s is too large", p.curToken.Literal)
+		return &ast.BadExpression{Token: lit.Token, Partial: lit}
 	}
 
 	lit.Value = value
@@ -294,6 +599,37 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+// parseInterpolatedString parses a string such as "Hello {name}". Each
+// embedded expression is lexed and parsed on its own, positioned where it
+// sits inside the string.
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	str := &ast.InterpolatedString{Token: p.curToken}
+
+	for _, part := range lexer.SplitInterpolation(p.curToken) {
+		partTok := token.Token{Type: token.STRING, Literal: part.Text, Line: part.Line, Column: part.Column}
+		if !part.IsExpr {
+			str.Parts = append(str.Parts, &ast.StringLiteral{Token: partTok, Value: part.Text})
+			continue
+		}
+		if strings.TrimSpace(part.Text) == "" {
+			p.addError(partTok, diagnostic.CodeExpectedExpr, "empty {} in string; write \\{ for a literal brace")
+			continue
+		}
+
+		l := lexer.NewAt(part.Text, part.Line, part.Column)
+		sub := New(l)
+		expr := sub.parseExpression(LOWEST)
+		if !sub.peekTokenIs(token.EOF) {
+			sub.addError(sub.peekToken, diagnostic.CodeUnexpectedToken, "expected } to end the expression in string, got %s", sub.peekToken.Type)
+		}
+		p.errors = append(p.errors, l.Diagnostics()...)
+		p.errors = append(p.errors, sub.errors...)
+		str.Parts = append(str.Parts, expr)
+	}
+
+	return str
+}
+
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.BooleanLiteral{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
 }
@@ -304,8 +640,13 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 		Operator: p.curToken.Literal, // Operator will be the keyword like "not"
 	}
 
+	rightPrecedence := PREFIX_PREC
+	if p.curTokenIs(token.NOT) { // 'not' applies to a whole comparison
+		rightPrecedence = NOT_PREC
+	}
+
 	p.nextToken()
-	expression.Right = p.parseExpression(PREFIX_PREC) // Parse the right-hand side expression
+	expression.Right = p.parseExpression(rightPrecedence) // Parse the right-hand side expression
 
 	return expression
 }
@@ -324,15 +665,64 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
-func (p *Parser) parseGroupedExpression() ast.Expression {
-	// In WordLang, we might not have parentheses for grouping in the traditional sense.
-	// We could use keywords for explicit grouping if needed, but for now, we'll skip explicit grouping for this basic example.
-	// If we decide to add grouping later (e.g., with "group ... end group" keywords), this is where we'd handle it.
+// arithmeticConnectives maps each arithmetic verb to the word separating its operands:
+// "add A and B", "sub A from B", "mult A by B", "div A by B".
+var arithmeticConnectives = map[token.TokenType]token.TokenType{
+	token.ADD:      token.AND,
+	token.SUBTRACT: token.FROM,
+	token.MULTIPLY: token.BY,
+	token.DIVIDE:   token.BY,
+}
+
+// parseArithmeticExpression parses the verb-first arithmetic forms into an InfixExpression.
+// "add 5 to x" is the in-place form: x receives the total.
+func (p *Parser) parseArithmeticExpression() ast.Expression {
+	expression := &ast.InfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal, // "add", "sub", "mult" or "div"
+	}
+	verb := p.curToken.Type
+
+	p.nextToken() // Consume the verb
+	first := p.parseExpression(SUM_PREC)
 
-	// For now, just return the inner expression as if there were no grouping.
-	p.nextToken() // Consume the opening group keyword (if we had one)
+	if verb == token.ADD && p.peekTokenIs(token.TO) {
+		p.nextToken() // Consume 'to'
+		if !p.expectPeek(token.IDENT) { // Only a variable can receive the total
+			return &ast.BadExpression{Token: expression.Token, Partial: expression}
+		}
+		expression.Left = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		expression.Right = first
+		expression.InPlace = true
+		return expression
+	}
+
+	if !p.expectPeek(arithmeticConnectives[verb]) {
+		return &ast.BadExpression{Token: expression.Token, Partial: expression}
+	}
+	p.nextToken() // Consume the connective
+	second := p.parseExpression(SUM_PREC)
+
+	if verb == token.SUBTRACT { // "sub A from B" computes B - A
+		expression.Left, expression.Right = second, first
+	} else {
+		expression.Left, expression.Right = first, second
+	}
+
+	return expression
+}
+
+// parseGroupedExpression parses 'group ... endgroup', WordLang's word for parentheses.
+// The grouping leaves no node of its own; it only overrides precedence.
+func (p *Parser) parseGroupedExpression() ast.Expression {
+	groupToken := p.curToken
+	p.nextToken() // Consume 'group'
 	exp := p.parseExpression(LOWEST)
-	// Expect closing group keyword (if we had one)
+
+	if !p.expectPeek(token.ENDGROUP) {
+		return &ast.BadExpression{Token: groupToken, Partial: exp}
+	}
+
 	return exp
 }
 // Change return type to ast.Statement
@@ -342,129 +732,451 @@ func (p *Parser) parseIfStatement() ast.Statement {
 	p.nextToken() // Consume 'if'
 	stmt.Condition = p.parseExpression(LOWEST)
 
-	if !p.expectPeek(token.THEN) { // Expect 'then' after condition
-		return nil
+	if !p.expectHeaderEnd(token.THEN) { // Expect 'then' after condition
+		p.skipBlock(stmt.Token, p.parseBlockStatement, token.ENDIF)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
 	}
 
-	stmt.ThenBlock = p.parseBlockStatement() // Parse the 'then' block
+	stmt.ThenBlock = p.parseBlockStatement() // Parse the 'then' block, stops on its terminator
 
-	for p.peekTokenIs(token.ELSEIF) { // Handle multiple 'elseif' blocks
+	for p.curTokenIs(token.ELSEIF) { // Handle multiple 'elseif' blocks
 		p.nextToken() // Consume 'elseif'
 		elseifBlock := &ast.ElseIfBlock{}
 		elseifBlock.Condition = p.parseExpression(LOWEST)
-		if !p.expectPeek(token.THEN) {
-			return nil
+		if !p.expectHeaderEnd(token.THEN) {
+			p.skipBlock(stmt.Token, p.parseBlockStatement, token.ENDIF)
+			return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
 		}
 		elseifBlock.Block = p.parseBlockStatement()
 		stmt.ElseIfBlocks = append(stmt.ElseIfBlocks, elseifBlock)
 	}
 
-
-	if p.peekTokenIs(token.ELSE) {
-		p.nextToken() // Consume 'else'
+	if p.curTokenIs(token.ELSE) {
 		stmt.ElseBlock = p.parseBlockStatement() // Parse the 'else' block
 	}
 
-	if !p.expectPeek(token.ENDIF) { // Expect 'endif' to close the if statement
-		return nil
+	if !p.expectCur(token.ENDIF) { // Expect 'endif' to close the if statement
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
 	}
 
 	return stmt // Still return the *ast.IfStatement, which now satisfies ast.Statement
 }
 
+// parseWhenStatement parses 'when <subject>' followed by one or more 'is' arms,
+// an optional 'otherwise' and 'endwhen'.
+func (p *Parser) parseWhenStatement() ast.Statement {
+	stmt := &ast.WhenStatement{Token: p.curToken}
+
+	p.nextToken() // Consume 'when'
+	stmt.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.IS) { // At least one arm
+		p.skipBlock(stmt.Token, p.parseBlockStatement, token.ENDWHEN)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	for p.curTokenIs(token.IS) {
+		arm := p.parseWhenArm()
+		if arm == nil {
+			p.skipBlock(stmt.Token, p.parseBlockStatement, token.ENDWHEN)
+			return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+		}
+		stmt.Arms = append(stmt.Arms, arm)
+	}
+
+	if p.curTokenIs(token.OTHERWISE) {
+		stmt.Otherwise = p.parseBlockStatement()
+	}
+
+	if !p.expectCur(token.ENDWHEN) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	return stmt
+}
+
+// parseWhenArm parses 'is <value> [or <value>...]' or 'is between <low> and
+// <high>', and the block after it. The values are parsed above 'or' and
+// 'and', which separate them here.
+func (p *Parser) parseWhenArm() *ast.WhenArm {
+	arm := &ast.WhenArm{Token: p.curToken}
+
+	if p.peekTokenIs(token.BETWEEN) {
+		p.nextToken()
+		p.nextToken() // Consume 'between', move to the low end
+		arm.Low = p.parseExpression(AND_PREC)
+		if !p.expectPeek(token.AND) {
+			return nil
+		}
+		p.nextToken()
+		arm.High = p.parseExpression(AND_PREC)
+	} else {
+		p.nextToken() // Consume 'is', move to the first value
+		arm.Values = append(arm.Values, p.parseExpression(OR_PREC))
+		for p.peekTokenIs(token.OR) {
+			p.nextToken()
+			p.nextToken()
+			arm.Values = append(arm.Values, p.parseExpression(OR_PREC))
+		}
+	}
+
+	arm.Body = p.parseBlockStatement() // Stops on the next arm, 'otherwise' or 'endwhen'
+	return arm
+}
+
+// parseTryStatement parses 'try ... [if it fails [as <name>] ...] [always ...] endtry'.
+func (p *Parser) parseTryStatement() ast.Statement {
+	stmt := &ast.TryStatement{Token: p.curToken}
+
+	stmt.Body = p.parseBlockStatement()
+
+	if p.curTokenIs(token.IFITFAILS) {
+		if p.peekTokenIs(token.AS) {
+			p.nextToken()
+			if !p.expectPeek(token.IDENT) {
+				p.skipBlock(stmt.Token, p.parseBlockStatement, token.ENDTRY)
+				return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+			}
+			stmt.ErrorName = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		}
+		stmt.Handler = p.parseBlockStatement()
+	}
+
+	if p.curTokenIs(token.ALWAYS) {
+		stmt.Always = p.parseBlockStatement()
+	}
+
+	if !p.expectCur(token.ENDTRY) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseRaiseStatement() ast.Statement {
+	stmt := &ast.RaiseStatement{Token: p.curToken}
+
+	p.nextToken() // Consume 'raise'
+	stmt.Message = p.parseExpression(LOWEST)
+
+	return stmt
+}
+
+// blockTerminators are the tokens that end a block of statements.
+var blockTerminators = map[token.TokenType]bool{
+	token.ENDIF:       true,
+	token.ELSE:        true,
+	token.ELSEIF:      true,
+	token.ENDWHILE:    true,
+	token.ENDFOREACH:  true,
+	token.ENDREPEAT:   true,
+	token.ENDFOR:      true,
+	token.IS:          true, // Next arm of 'when'
+	token.OTHERWISE:   true,
+	token.ENDWHEN:     true,
+	token.IFITFAILS:   true,
+	token.ALWAYS:      true,
+	token.ENDTRY:      true,
+	token.ENDFUNCTION: true,
+	token.END:         true,
+	token.EOF:         true,
+}
+
+// parseBlockStatement parses statements up to a block terminator.
+// It returns with curToken on the terminator, so callers check it with expectCur.
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken, Statements: []ast.Statement{}}
 
 	p.nextToken() // Consume '{' (though we don't have explicit braces in WordLang, this conceptually starts the block)
 
-	for !p.curTokenIs(token.ENDIF) && !p.curTokenIs(token.ELSE) && !p.curTokenIs(token.ELSEIF) && !p.curTokenIs(token.ENDWHILE) && !p.curTokenIs(token.ENDFOREACH) && !p.curTokenIs(token.END) && !p.curTokenIs(token.EOF) { // Stop at block terminators or EOF
-		stmt := p.parseStatement()
+	for !blockTerminators[p.curToken.Type] { // Stop at block terminators or EOF
+		stmt := p.parseNextStatement()
 		if stmt != nil {
 			block.Statements = append(block.Statements, stmt)
 		}
-		p.nextToken()
 	}
 
 	return block
 }
 
-func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+func (p *Parser) parseWhileStatement() ast.Statement {
 	stmt := &ast.WhileStatement{Token: p.curToken}
 
 	p.nextToken() // Consume 'while'
 	stmt.Condition = p.parseExpression(LOWEST)
 
-	if !p.expectPeek(token.DO) { // Expect 'do' after condition
-		return nil
+	if !p.parseLoopLabel(&stmt.Label) || !p.expectHeaderEnd(token.DO) { // Expect 'do' after condition
+		p.skipLoop(stmt.Token, stmt.Label, token.ENDWHILE)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
 	}
 
-	stmt.Body = p.parseBlockStatement() // Parse the loop body
+	stmt.Body = p.parseLoopBody(stmt.Label) // Parse the loop body
 
-	if !p.expectPeek(token.ENDWHILE) { // Expect 'endwhile' to close the while loop
-		return nil
+	if !p.expectCur(token.ENDWHILE) { // Expect 'endwhile' to close the while loop
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
 	}
 
 	return stmt
 }
 
-func (p *Parser) parseForEachStatement() *ast.ForEachStatement {
+func (p *Parser) parseForEachStatement() ast.Statement {
 	stmt := &ast.ForEachStatement{Token: p.curToken}
 
 	if !p.expectPeek(token.IDENT) { // Expect identifier for variable name
-		return nil
+		p.skipLoop(stmt.Token, stmt.Label, token.ENDFOREACH)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
 	}
 	stmt.Variable = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
 	if !p.expectPeek(token.IN) { // Expect 'in' keyword
-		return nil
+		p.skipLoop(stmt.Token, stmt.Label, token.ENDFOREACH)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
 	}
 
 	p.nextToken() // Consume 'in'
 	stmt.Iterable = p.parseExpression(LOWEST) // Parse the iterable expression (should be a list)
 
-	if !p.expectPeek(token.DO) { // Expect 'do' before loop body
-		return nil
+	if !p.parseLoopLabel(&stmt.Label) || !p.expectHeaderEnd(token.DO) { // Expect 'do' before loop body
+		p.skipLoop(stmt.Token, stmt.Label, token.ENDFOREACH)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	stmt.Body = p.parseLoopBody(stmt.Label) // Parse the loop body
+
+	if !p.expectCur(token.ENDFOREACH) { // Expect 'endforeach' to close the loop
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	return stmt
+}
+
+// parseRepeatStatement parses 'repeat <count> times do ... endrepeat'.
+func (p *Parser) parseRepeatStatement() ast.Statement {
+	stmt := &ast.RepeatStatement{Token: p.curToken}
+
+	p.nextToken() // Consume 'repeat'
+	stmt.Count = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.TIMES) || !p.parseLoopLabel(&stmt.Label) || !p.expectHeaderEnd(token.DO) {
+		p.skipLoop(stmt.Token, stmt.Label, token.ENDREPEAT)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	stmt.Body = p.parseLoopBody(stmt.Label)
+
+	if !p.expectCur(token.ENDREPEAT) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	return stmt
+}
+
+// parseForStatement parses 'for <name> from <start> to <end> [step <step>] do ... endfor'.
+func (p *Parser) parseForStatement() ast.Statement {
+	stmt := &ast.ForStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) { // The loop variable
+		p.skipLoop(stmt.Token, stmt.Label, token.ENDFOR)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	stmt.Variable = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.FROM) {
+		p.skipLoop(stmt.Token, stmt.Label, token.ENDFOR)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.Start = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.TO) {
+		p.skipLoop(stmt.Token, stmt.Label, token.ENDFOR)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.End = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.STEP) {
+		p.nextToken()
+		p.nextToken()
+		stmt.Step = p.parseExpression(LOWEST)
+	}
+
+	if !p.parseLoopLabel(&stmt.Label) || !p.expectHeaderEnd(token.DO) {
+		p.skipLoop(stmt.Token, stmt.Label, token.ENDFOR)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	stmt.Body = p.parseLoopBody(stmt.Label)
+
+	if !p.expectCur(token.ENDFOR) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	return stmt
+}
+
+// parseIncrementStatement parses 'increment <name> [by <amount>]', and the
+// list form 'increment item at index <index> of <name> [by <amount>]'.
+// 'decrement' takes the same forms.
+func (p *Parser) parseIncrementStatement() ast.Statement {
+	stmt := &ast.IncrementStatement{Token: p.curToken}
+
+	if p.peekTokenIs(token.ITEMATINDEX) {
+		p.nextToken()
+		p.nextToken() // Consume 'item at index', move to the index expression
+		stmt.Index = p.parseExpression(LOWEST)
+		if !p.expectPeek(token.OF) {
+			return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+		}
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.BY) {
+		p.nextToken()
+		p.nextToken() // Consume 'by', move to the amount
+		stmt.Amount = p.parseExpression(LOWEST)
+	}
+
+	return stmt
+}
+
+// parseLoopLabel parses the optional 'named <label>' at the end of a loop
+// header into label. It reports false if 'named' is not followed by a name.
+func (p *Parser) parseLoopLabel(label **ast.Identifier) bool {
+	if p.panicking || !p.peekTokenIs(token.NAMED) {
+		return true
+	}
+	p.nextToken()
+	if !p.expectPeek(token.IDENT) {
+		return false
+	}
+	*label = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	return true
+}
+
+// parseLoopBody parses the body of a loop, with the loop on the stack that
+// 'stop' and 'skip' are checked against.
+func (p *Parser) parseLoopBody(label *ast.Identifier) *ast.BlockStatement {
+	name := ""
+	if label != nil {
+		name = label.Value
+	}
+	p.loops = append(p.loops, name)
+	defer func() { p.loops = p.loops[:len(p.loops)-1] }()
+
+	return p.parseBlockStatement()
+}
+
+// parseStopStatement parses 'stop', 'stop loop' or 'stop <label>'.
+func (p *Parser) parseStopStatement() ast.Statement {
+	stmt := &ast.StopStatement{Token: p.curToken}
+	stmt.Label = p.parseLoopJumpLabel("stop")
+	return stmt
+}
+
+// parseSkipStatement parses 'skip', 'skip to next' or 'skip <label>'.
+func (p *Parser) parseSkipStatement() ast.Statement {
+	stmt := &ast.SkipStatement{Token: p.curToken}
+	stmt.Label = p.parseLoopJumpLabel("skip")
+	return stmt
+}
+
+// parseLoopJumpLabel parses the optional label after 'stop' or 'skip' and
+// checks that the statement is inside a loop with that label. Loops outside
+// the enclosing function do not count.
+func (p *Parser) parseLoopJumpLabel(keyword string) *ast.Identifier {
+	jump := p.curToken
+	var label *ast.Identifier
+	if p.peekTokenIs(token.IDENT) && p.peekOnSameLine() {
+		p.nextToken()
+		label = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if len(p.loops) == 0 {
+		p.addMisplacedError(jump, "'%s' can only be used inside a loop", keyword)
+		return label
+	}
+	if label != nil {
+		for _, name := range p.loops {
+			if name == label.Value {
+				return label
+			}
+		}
+		p.addMisplacedError(label.Token, "no loop named %s around this '%s'", label.Value, keyword)
 	}
+	return label
+}
 
-	stmt.Body = p.parseBlockStatement() // Parse the loop body
+// parseFunctionStatement parses a named declaration: 'function greet person name ... endfunction'.
+func (p *Parser) parseFunctionStatement() ast.Statement {
+	stmt := &ast.FunctionStatement{Token: p.curToken, Doc: p.curDoc}
 
-	if !p.expectPeek(token.ENDFOREACH) { // Expect 'endforeach' to close the loop
-		return nil
+	if !p.expectPeek(token.IDENT) { // Expect the function name
+		p.skipBlock(stmt.Token, p.parseFunctionBody, token.ENDFUNCTION, token.END)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	stmt.Function = &ast.FunctionLiteral{Token: stmt.Token}
+	if !p.parseFunctionRest(stmt.Function) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
 	}
 
 	return stmt
 }
 
-// Change return type to ast.Expression
-func (p *Parser) parseFunctionStatement() ast.Expression { 
-    lit := &ast.FunctionLiteral{Token: p.curToken}
+// parseFunctionLiteral parses an anonymous function value: 'let double be function n ... endfunction'.
+func (p *Parser) parseFunctionLiteral() ast.Expression {
+	lit := &ast.FunctionLiteral{Token: p.curToken}
+	if !p.parseFunctionRest(lit) {
+		return &ast.BadExpression{Token: lit.Token, Partial: lit}
+	}
+	return lit
+}
 
-    if p.peekTokenIs(token.IDENT) { // Parameters are optional for now, but if present, expect IDENTs
-        p.nextToken()
-        lit.Parameters = p.parseFunctionParameters()
-    } else {
-        lit.Parameters = []*ast.Identifier{} // No parameters
-    }
+// parseFunctionRest parses the parameters and body following 'function' (or the function name).
+func (p *Parser) parseFunctionRest(lit *ast.FunctionLiteral) bool {
+	if p.peekTokenIs(token.IDENT) && p.peekOnSameLine() { // Parameters are the identifiers on the header line
+		p.nextToken()
+		lit.Parameters = p.parseFunctionParameters()
+	} else {
+		lit.Parameters = []*ast.Identifier{} // No parameters
+	}
+
+	lit.Body = p.parseFunctionBody()
+
+	if !p.curTokenIs(token.ENDFUNCTION) && !p.curTokenIs(token.END) { // Expect 'end function' or 'end' to close function definition
+		p.curError(token.ENDFUNCTION)
+		return false
+	}
 
-    lit.Body = p.parseBlockStatement() // Parse function body
+	return true
+}
 
-    if !p.expectPeek(token.ENDFUNCTION) && !p.expectPeek(token.END) { // Expect 'end function' or 'end' to close function definition
-        return nil // Or handle error appropriately
-    }
+// parseFunctionBody parses a function body, which cannot stop or skip the loops
+// around the function's declaration.
+func (p *Parser) parseFunctionBody() *ast.BlockStatement {
+	outerLoops := p.loops
+	p.loops = nil
+	defer func() { p.loops = outerLoops }()
 
-    return lit // Return the *ast.FunctionLiteral, which now satisfies ast.Expression
+	return p.parseBlockStatement()
 }
 
 func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 	identifiers := []*ast.Identifier{}
 
-	if !p.curTokenIs(token.IDENT) { // No parameters case handled in parseFunctionStatement
+	if !p.curTokenIs(token.IDENT) { // No parameters case handled in parseFunctionRest
 		return identifiers
 	}
 
 	identifiers = append(identifiers, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
 
-	for p.peekTokenIs(token.IDENT) { // Expect more identifiers (parameters), separated by spaces (or commas if we decide to add them back minimally)
+	for p.peekTokenIs(token.IDENT) && p.peekOnSameLine() { // More parameters, separated by spaces; the body starts on the next line
 		p.nextToken()
 		identifiers = append(identifiers, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
 	}
@@ -472,39 +1184,88 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 	return identifiers
 }
 
-
-func (p *Parser) parseCallStatement() *ast.CallExpression { // Changed to CallExpression as function calls are expressions (for now)
+// parseCallExpression parses 'call greet "User" "WordLang Learner"'.
+// Calls are expressions, so they work as statements and inside 'let x be call double 4'.
+func (p *Parser) parseCallExpression() ast.Expression {
 	callExp := &ast.CallExpression{Token: p.curToken}
 
-	p.nextToken() // Consume 'call'
-
-	callExp.Function = p.parseExpression(CALL_PREC) // Parse function identifier or function literal
+	if p.peekIsName() && !p.peekStartsExpression() { // 'call input' names an imported builtin, not the statement keyword
+		p.nextToken()
+		callExp.Function = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	} else {
+		p.nextToken() // Consume 'call'
+		callExp.Function = p.parseExpression(CALL_PREC) // Parse function identifier or function literal
+	}
 
 	callExp.Arguments = p.parseCallArguments()
 
 	return callExp
 }
 
+// parseCallArguments reads the arguments following the function, up to the end of the line.
+// Each argument is a single operand; use 'group ... endgroup' to pass a comparison.
 func (p *Parser) parseCallArguments() []ast.Expression {
 	args := []ast.Expression{}
 
-	if p.peekTokenIs(token.END) { // No arguments
+	for p.peekStartsExpression() && p.peekOnSameLine() {
 		p.nextToken()
-		return args
+		args = append(args, p.parseExpression(PREFIX_PREC))
 	}
 
-	p.nextToken() // Move to the first argument
-	args = append(args, p.parseExpression(LOWEST))
+	return args
+}
+
+// peekStartsExpression reports whether the next token can begin an expression.
+func (p *Parser) peekStartsExpression() bool {
+	_, ok := p.prefixParseFns[p.peekToken.Type]
+	return ok
+}
+
+// peekOnSameLine reports whether the next token is on the current token's line.
+// WordLang has no separators, so line ends delimit parameter and argument lists.
+func (p *Parser) peekOnSameLine() bool {
+	return p.peekToken.Line == p.curToken.Line
+}
 
-	for p.peekTokenIs(token.IDENT) || p.peekTokenIs(token.NUMBER) || p.peekTokenIs(token.STRING) || p.peekTokenIs(token.TRUE) || p.peekTokenIs(token.FALSE) || p.peekTokenIs(token.LIST) || p.peekTokenIs(token.GETITEMATINDEX) || p.peekTokenIs(token.CONVERTTONUMBER) || p.peekTokenIs(token.CONVERTTOSTRING){ // Check for tokens that can start an expression argument
+// parseImportStatement parses 'from stdio import print' and 'from "lib/helpers.wl" import double and triple'.
+func (p *Parser) parseImportStatement() ast.Statement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+
+	if !p.peekIsName() && !p.peekTokenIs(token.STRING) { // 'strings' is a keyword as well as a module
+		p.peekError(token.IDENT)
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+	p.nextToken()
+	stmt.Module = p.curToken.Literal
+
+	if !p.expectPeek(token.IMPORT) {
+		return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+	}
+
+	for {
+		if !p.peekIsName() {
+			p.peekError(token.IDENT)
+			return &ast.BadStatement{Token: stmt.Token, Partial: stmt}
+		}
 		p.nextToken()
-		args = append(args, p.parseExpression(LOWEST))
+		stmt.Names = append(stmt.Names, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+		if !p.peekTokenIs(token.AND) {
+			break
+		}
+		p.nextToken() // Consume 'and'
 	}
 
-	return args
+	return stmt
 }
 
-func (p *Parser) parsePrintStatement() *ast.PrintStatement {
+// peekIsName reports whether the next token can name an imported binding.
+// Single-word keywords are allowed too, so 'from stdio import print' works.
+func (p *Parser) peekIsName() bool {
+	return p.peekTokenIs(token.IDENT) || token.LookupIdent(p.peekToken.Literal) == p.peekToken.Type
+}
+
+func (p *Parser) parsePrintStatement() ast.Statement {
 	stmt := &ast.PrintStatement{Token: p.curToken}
 
 	p.nextToken() // Consume 'print'
@@ -514,7 +1275,7 @@ func (p *Parser) parsePrintStatement() *ast.PrintStatement {
 	return stmt
 }
 
-func (p *Parser) parseInputStatement() *ast.InputStatement {
+func (p *Parser) parseInputStatement() ast.Statement {
 	stmt := &ast.InputStatement{Token: p.curToken}
 
 	if p.peekTokenIs(token.STRING) { // Optional prompt string
@@ -532,51 +1293,106 @@ func (p *Parser) parseListLiteral() ast.Expression {
 		p.nextToken()
 		return listLit
 	}
+	if !p.peekOnSameLine() { // Empty 'strings', 'numbers' or 'decimals'; the next line is a new statement
+		return listLit
+	}
 
 	p.nextToken() // Move to the first element
 	listLit.Elements = append(listLit.Elements, p.parseExpression(LOWEST))
 
-	for p.peekTokenIs(token.IDENT) || p.peekTokenIs(token.NUMBER) || p.peekTokenIs(token.STRING) || p.peekTokenIs(token.TRUE) || p.peekTokenIs(token.FALSE) || p.peekTokenIs(token.LIST) || p.peekTokenIs(token.GETITEMATINDEX) || p.peekTokenIs(token.CONVERTTONUMBER) || p.peekTokenIs(token.CONVERTTOSTRING){ // Check for tokens that can start an expression list element
+	for listElementStarts[p.peekToken.Type] && p.peekOnSameLine() { // The elements are on the list's line, whether or not 'end' closes it
 		p.nextToken()
 		listLit.Elements = append(listLit.Elements, p.parseExpression(LOWEST))
 	}
 
+	if p.peekTokenIs(token.END) && p.peekOnSameLine() { // Optional closing 'end'; on a later line it closes a block
+		p.nextToken()
+	}
+
 	return listLit
 }
 
-func (p *Parser) parseGetItemAtIndexExpression(list ast.Expression) ast.Expression {
-	getItemAtIndexExp := &ast.GetItemAtIndexExpression{Token: p.curToken, List: list}
+// parseDictionaryLiteral parses 'dictionary' and
+// 'dictionary with <key> as <value> [and <key> as <value>...]'. Keys and
+// values are parsed above 'and', which separates the entries.
+func (p *Parser) parseDictionaryLiteral() ast.Expression {
+	dict := &ast.DictionaryLiteral{Token: p.curToken}
 
-	if !p.expectPeek(token.INDEX) {
-		return nil
+	if !p.peekTokenIs(token.WITH) { // Empty dictionary
+		return dict
 	}
-	p.nextToken() // Consume 'index'
-	getItemAtIndexExp.Index = p.parseExpression(LOWEST)
+	p.nextToken()
 
-	if !p.expectPeek(token.FROM) {
-		return nil
+	for {
+		p.nextToken() // Consume 'with' or 'and', move to the key
+		dict.Keys = append(dict.Keys, p.parseExpression(AND_PREC))
+		if !p.expectPeek(token.AS) {
+			return &ast.BadExpression{Token: dict.Token, Partial: dict}
+		}
+		p.nextToken()
+		dict.Values = append(dict.Values, p.parseExpression(AND_PREC))
+
+		if !p.peekTokenIs(token.AND) {
+			break
+		}
+		p.nextToken()
 	}
-	p.nextToken() // consume 'from'
-	// List is already parsed and passed as 'list' argument to this function
 
-	return getItemAtIndexExp
+	return dict
+}
+
+// parseValueForExpression parses 'value for <key> in <dictionary>'.
+func (p *Parser) parseValueForExpression() ast.Expression {
+	expr := &ast.ValueForExpression{Token: p.curToken}
+
+	p.nextToken() // Consume 'value for', move to the key
+	expr.Key = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.IN) {
+		return &ast.BadExpression{Token: expr.Token, Partial: expr}
+	}
+	p.nextToken()
+	expr.Dictionary = p.parseExpression(PREFIX_PREC) // So that 'value for k in d equals 90' compares the value
+
+	return expr
+}
+
+// listElementStarts are the tokens that continue a list literal with another element.
+var listElementStarts = map[token.TokenType]bool{
+	token.IDENT:           true,
+	token.INT:             true,
+	token.FLOAT:           true,
+	token.MINUS:           true,
+	token.STRING:          true,
+	token.INTERPOLATED:    true,
+	token.TRUE:            true,
+	token.FALSE:           true,
+	token.LIST:            true,
+	token.STRINGS:         true,
+	token.NUMBERS:         true,
+	token.DECIMALS:        true,
+	token.DICTIONARY:      true,
+	token.VALUEFOR:        true,
+	token.GETITEMATINDEX:  true,
+	token.CONVERTTONUMBER: true,
+	token.CONVERTTOSTRING: true,
 }
 
 func (p *Parser) parseIsDefinedExpression() ast.Expression {
 	isDefinedExp := &ast.IsDefinedExpression{Token: p.curToken}
 
 	if !p.expectPeek(token.IDENT) {
-		return nil // Expect identifier after 'is defined'
+		return &ast.BadExpression{Token: isDefinedExp.Token, Partial: isDefinedExp}
 	}
 	isDefinedExp.Identifier = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
 	return isDefinedExp
 }
 
-func (p *Parser) parseExitStatement() *ast.ExitStatement {
+func (p *Parser) parseExitStatement() ast.Statement {
 	stmt := &ast.ExitStatement{Token: p.curToken}
 
-	if !p.peekTokenIs(token.END) && !p.peekTokenIs(token.EOF) { // Optional exit code
+	if p.peekStartsExpression() && p.peekOnSameLine() { // Optional exit code, on the same line
 		p.nextToken()
 		stmt.Code = p.parseExpression(LOWEST)
 	}
@@ -606,21 +1422,43 @@ func (p *Parser) registerParseFunctions() {
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	// --- Prefix Parsing Functions (Simplified) ---
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
-	p.registerPrefix(token.NUMBER, p.parseIntegerLiteral)
+	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
+	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.INTERPOLATED, p.parseInterpolatedString)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
-	// REMOVE: p.registerPrefix(token.NOT, p.parsePrefixExpression)
-	p.registerPrefix(token.FUNCTION, p.parseFunctionStatement) // Function literal as expression
+	p.registerPrefix(token.NOT, p.parsePrefixExpression)
+	p.registerPrefix(token.GROUP, p.parseGroupedExpression)
+	p.registerPrefix(token.ADD, p.parseArithmeticExpression)
+	p.registerPrefix(token.SUBTRACT, p.parseArithmeticExpression)
+	p.registerPrefix(token.MULTIPLY, p.parseArithmeticExpression)
+	p.registerPrefix(token.DIVIDE, p.parseArithmeticExpression)
+	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral) // Function literal as expression
+	p.registerPrefix(token.CALL, p.parseCallExpression)
 	p.registerPrefix(token.LIST, p.parseListLiteral)
+	p.registerPrefix(token.STRINGS, p.parseListLiteral)
+	p.registerPrefix(token.NUMBERS, p.parseListLiteral)
+	p.registerPrefix(token.DECIMALS, p.parseListLiteral)
+	p.registerPrefix(token.DICTIONARY, p.parseDictionaryLiteral)
+	p.registerPrefix(token.VALUEFOR, p.parseValueForExpression)
 	p.registerPrefix(token.GETITEMATINDEX, p.parseGetItemAtIndexPrefix)
 	p.registerPrefix(token.ISDEFINED, p.parseIsDefinedExpression)
 	p.registerPrefix(token.CONVERTTONUMBER, p.parseConvertToNumberExpression)
 	p.registerPrefix(token.CONVERTTOSTRING, p.parseConvertToStringExpression)
 
 
-	// --- REMOVE ALL INFIX PARSING REGISTRATIONS ---
-	// REMOVE: p.registerInfix(token.ADD, p.parseInfixExpression)
+	// --- Infix Parsing Functions ---
+	p.registerInfix(token.EQUALS, p.parseInfixExpression)
+	p.registerInfix(token.NOTEQUALS, p.parseInfixExpression)
+	p.registerInfix(token.GREATERTHAN, p.parseInfixExpression)
+	p.registerInfix(token.LESSTHAN, p.parseInfixExpression)
+	p.registerInfix(token.GREATEREQUAL, p.parseInfixExpression)
+	p.registerInfix(token.LESSEQUAL, p.parseInfixExpression)
+	p.registerInfix(token.AND, p.parseInfixExpression)
+	p.registerInfix(token.OR, p.parseInfixExpression)
+	p.registerInfix(token.HASKEY, p.parseInfixExpression)
 
 	// --- Statement Parsing Registrations (NEW) ---
 	p.registerStatement(token.LET, p.parseLetStatement)
@@ -630,55 +1468,68 @@ func (p *Parser) registerParseFunctions() {
 	p.registerStatement(token.FOREACH, p.parseForEachStatement)
     p.registerStatement(token.RETURN, p.parseReturnStatement)
 	p.registerStatement(token.EXIT, p.parseExitStatement)
+	p.registerStatement(token.STOP, p.parseStopStatement)
+	p.registerStatement(token.REPEAT, p.parseRepeatStatement)
+	p.registerStatement(token.FOR, p.parseForStatement)
+	p.registerStatement(token.INCREMENT, p.parseIncrementStatement)
+	p.registerStatement(token.DECREMENT, p.parseIncrementStatement)
+	p.registerStatement(token.SET, p.parseSetStatement)
+	p.registerStatement(token.WHEN, p.parseWhenStatement)
+	p.registerStatement(token.TRY, p.parseTryStatement)
+	p.registerStatement(token.RAISE, p.parseRaiseStatement)
+	p.registerStatement(token.REMOVE, p.parseRemoveStatement)
+	p.registerStatement(token.APPEND, p.parseAppendStatement)
+	p.registerStatement(token.INSERT, p.parseInsertStatement)
+	p.registerStatement(token.SKIP, p.parseSkipStatement)
 	p.registerStatement(token.INPUT, p.parseInputStatement)
-	//Add function call statement if applicable:  p.registerStatement(token.CALL, p.parseCallStatement)
+	p.registerStatement(token.FUNCTION, p.parseFunctionStatement)
+	p.registerStatement(token.FROM, p.parseImportStatement)
 }
 
 func (p *Parser) parseGetItemAtIndexPrefix() ast.Expression {
-	p.nextToken() // Consume 'get item at index' and move to next token which should be index expression.
-	indexExp := p.parseExpression(LOWEST)
-
-	if !p.expectPeek(token.FROM) {
-		return nil
-	}
-	p.nextToken() // Consume 'from'
-	listExp := p.parseExpression(LOWEST)
-
-	return &ast.GetItemAtIndexExpression{
-		Token: p.curToken, // Token context might need adjustment
-		List: listExp,
-		Index: indexExp,
-	}
-}
-
-func (p *Parser) parseGetItemAtIndexInfix(left ast.Expression) ast.Expression {
-	getItemAtIndexExp := &ast.GetItemAtIndexExpression{Token: p.curToken, List: left}
+	getItemAtIndexExp := &ast.GetItemAtIndexExpression{Token: p.curToken}
 
-	if !p.expectPeek(token.INDEX) {
-		return nil
-	}
-	p.nextToken() // Consume 'index'
+	p.nextToken() // Consume 'get item at index' and move to next token which should be index expression.
 	getItemAtIndexExp.Index = p.parseExpression(LOWEST)
 
 	if !p.expectPeek(token.FROM) {
-		return nil
+		return &ast.BadExpression{Token: getItemAtIndexExp.Token, Partial: getItemAtIndexExp}
 	}
-	p.nextToken() // consume 'from'
-	// List is already parsed as 'left' expression
+	p.nextToken() // Consume 'from'
+	getItemAtIndexExp.List = p.parseExpression(LOWEST)
 
 	return getItemAtIndexExp
 }
 
-
-func (p *Parser) parseCallExpressionInfix(function ast.Expression) ast.Expression {
-	callExp := &ast.CallExpression{Token: p.curToken, Function: function}
-	callExp.Arguments = p.parseCallArguments()
-	return callExp
+// Errors returns parsing errors as messages with their positions.
+func (p *Parser) Errors() []string {
+	msgs := make([]string, len(p.errors))
+	for i, d := range p.errors {
+		msgs[i] = fmt.Sprintf("%s at line %d, column %d", d.Message, d.Span.Start.Line, d.Span.Start.Column)
+	}
+	return msgs
 }
 
-
-// Errors returns parsing errors.
-func (p *Parser) Errors() []string {
+// Diagnostics returns parsing errors as diagnostics with source spans.
+func (p *Parser) Diagnostics() []*diagnostic.Diagnostic {
 	return p.errors
 }
 
+// Parse lexes and parses source. file names the source in the diagnostics,
+// which hold the lexer and parser errors in source order.
+func Parse(file, source string) (*ast.Program, []*diagnostic.Diagnostic) {
+	l := lexer.New(source)
+	p := New(l)
+	program := p.ParseProgram()
+
+	diagnostics := append([]*diagnostic.Diagnostic{}, l.Diagnostics()...)
+	diagnostics = append(diagnostics, p.Diagnostics()...)
+	for _, d := range diagnostics {
+		d.File = file
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool { // Interleave lexer and parser errors in source order
+		a, b := diagnostics[i].Span.Start, diagnostics[j].Span.Start
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return program, diagnostics
+}
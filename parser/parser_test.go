@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"testing"
+	"wordlang/ast"
+	"wordlang/lexer"
+)
+
+// parseErrors parses input and returns the lexer and parser error messages.
+func parseErrors(input string) []string {
+	l := lexer.New(input)
+	p := New(l)
+	p.ParseProgram()
+	return append(l.Errors(), p.Errors()...)
+}
+
+func TestParsesWithoutErrors(t *testing.T) {
+	tests := []string{
+		"let x be 5\nprint x",
+		"if x greater than 5 then\nprint 1\nelseif x equals 2 then\nprint 2\nelse\nprint 3\nendif",
+		"while x less than 3 named outer do\nstop loop outer\nendwhile",
+		"repeat 3 times do\nskip\nendrepeat",
+		"for i from 10 to 1 step -2 do\nprint i\nend for",
+		"let l be list 1 2 3 end\nprint l",
+		"function f n\nreturn list 1 2 end\nend",
+		"function double n\nreturn mult n by 2\nend\nfor i from 1 to 3 do\nprint i\nendfor",
+		"function f\nprint 1\nend\nrepeat 2 times do\nprint 2\nendrepeat",
+		"function f\nprint 1\nend\nwhen x\nis 1\nprint 1\nendwhen",
+		"function f\nprint 1\nend\ntry\nprint 1\nif it fails\nprint 2\nendtry",
+		"let value be 1\nprint value\nfor i from 1 to 2 do\nprint i\nendfor",
+		"when x\nis 1 or 2\nprint 1\nis between 3 and 10\nprint 2\notherwise\nprint 3\nendwhen",
+		"try\nraise \"no\"\nif it fails as e\nprint e\nalways\nprint 1\nendtry",
+		"let d be dictionary with \"a\" as 1 and \"b\" as 2\nprint value for \"a\" in d",
+		"let n be numbers 1 2\nappend 3 to n\ninsert 0 at index 0 of n\nremove item at index 1 from n\nset item at index 0 of n to 9",
+	}
+
+	for _, input := range tests {
+		if errs := parseErrors(input); len(errs) != 0 {
+			t.Errorf("%q: unexpected errors %v", input, errs)
+		}
+	}
+}
+
+// TestRecovery checks that a syntax error is reported once, without a
+// cascade of follow-on errors, and that parsing resumes after it.
+func TestRecovery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{
+			"if 1 equals then\nprint 1\nendif\nprint 2",
+			[]string{"expected an expression, got THEN at line 1, column 13"},
+		},
+		{
+			"while do\nprint 1\nendwhile",
+			[]string{"expected an expression, got DO at line 1, column 7"},
+		},
+		{
+			"let x 5\nprint x",
+			[]string{"expected next token to be BE, got INT instead at line 1, column 7"},
+		},
+		{
+			"let a be\nlet b be 2",
+			[]string{"expected an expression, got LET at line 2, column 1"},
+		},
+		{
+			"endif\nprint 1",
+			[]string{"unexpected ENDIF without a matching block at line 1, column 1"},
+		},
+		{
+			"let x 5\nlet y 6",
+			[]string{
+				"expected next token to be BE, got INT instead at line 1, column 7",
+				"expected next token to be BE, got INT instead at line 2, column 7",
+			},
+		},
+		{
+			"foreach in list 1 end do\nprint 1\nendforeach\nprint 2",
+			[]string{"expected next token to be IDENT, got IN instead at line 1, column 9"},
+		},
+		{
+			"function\nendfunction",
+			[]string{"expected next token to be IDENT, got ENDFUNCTION instead at line 2, column 1"},
+		},
+		{
+			"for from 1 to 2 do\nif x then\nprint 1\nendif\nendfor\nlet y 6",
+			[]string{
+				"expected next token to be IDENT, got FROM instead at line 1, column 5",
+				"expected next token to be BE, got INT instead at line 6, column 7",
+			},
+		},
+		{
+			"foreach in x do\nlet y 5\nendforeach\nprint 1",
+			[]string{
+				"expected next token to be IDENT, got IN instead at line 1, column 9",
+				"expected next token to be BE, got INT instead at line 2, column 7",
+			},
+		},
+		{
+			"while x y do\nstop\nendwhile",
+			[]string{"expected next token to be DO, got IDENT instead at line 1, column 9"},
+		},
+		{
+			"if x y then\nprint 1\nelse\nlet z 1\nendif",
+			[]string{
+				"expected next token to be THEN, got IDENT instead at line 1, column 6",
+				"expected next token to be BE, got INT instead at line 4, column 7",
+			},
+		},
+		{
+			"function f\nprint 1\nend for",
+			[]string{"expected ENDFUNCTION, got ENDFOR instead at line 3, column 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		errs := parseErrors(tt.input)
+		if len(errs) != len(tt.want) {
+			t.Errorf("%q: got errors %q, want %q", tt.input, errs, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if errs[i] != tt.want[i] {
+				t.Errorf("%q: error %d is %q, want %q", tt.input, i, errs[i], tt.want[i])
+			}
+		}
+	}
+}
+
+// TestListLiteralEndsWithLine checks that a list without 'end' takes no
+// elements from the next line.
+func TestListLiteralEndsWithLine(t *testing.T) {
+	tests := []struct {
+		input      string
+		elements   int
+		statements int
+	}{
+		{"let n be numbers 1 2\n-5", 2, 2},
+		{"let s be strings \"a\" \"b\"\nx", 2, 2},
+		{"let l be list 1 2 end\nx", 2, 2},
+		{"let d be decimals\nprint 1", 0, 2},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		if errs := append(l.Errors(), p.Errors()...); len(errs) != 0 {
+			t.Errorf("%q: unexpected errors %v", tt.input, errs)
+			continue
+		}
+		if len(program.Statements) != tt.statements {
+			t.Errorf("%q: got %d statements, want %d", tt.input, len(program.Statements), tt.statements)
+			continue
+		}
+		let := program.Statements[0].(*ast.LetStatement)
+		if list := let.Value.(*ast.ListLiteral); len(list.Elements) != tt.elements {
+			t.Errorf("%q: got %d elements, want %d", tt.input, len(list.Elements), tt.elements)
+		}
+	}
+}
+
+// TestBareExit checks that an 'exit' without a code leaves the next line alone.
+func TestBareExit(t *testing.T) {
+	for _, input := range []string{
+		"if x equals 1 then\n  exit\nendif",
+		"while true do\nexit\nendwhile",
+		"function f\nexit\nend",
+	} {
+		if errs := parseErrors(input); len(errs) != 0 {
+			t.Errorf("%q: unexpected errors %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input      string
+		exit       string
+		statements int
+	}{
+		{"exit\nprint 1", "exit", 2},
+		{"exit", "exit", 1},
+		{"exit 3\nprint 1", "exit 3", 2},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		if errs := append(l.Errors(), p.Errors()...); len(errs) != 0 {
+			t.Errorf("%q: unexpected errors %v", tt.input, errs)
+			continue
+		}
+		if len(program.Statements) != tt.statements {
+			t.Errorf("%q: got %d statements, want %d", tt.input, len(program.Statements), tt.statements)
+			continue
+		}
+		exit, ok := program.Statements[0].(*ast.ExitStatement)
+		if !ok || exit.String() != tt.exit {
+			t.Errorf("%q: got %s, want %s", tt.input, program.Statements[0], tt.exit)
+		}
+	}
+}
+
+func TestMisplacedLoopControl(t *testing.T) {
+	errs := parseErrors("stop\nwhile true do\nskip\nendwhile")
+	if len(errs) != 1 || errs[0] != "'stop' can only be used inside a loop at line 1, column 1" {
+		t.Errorf("got %q", errs)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"wordlang/ast"
+	"wordlang/diagnostic"
+	"wordlang/engine"
+	"wordlang/interpreter"
+	"wordlang/object"
+)
+
+const (
+	replPrompt         = "WordLang > "
+	replContinuePrompt = "       ... "
+)
+
+const replHelp = `Enter WordLang statements. Blocks (if, when, while, foreach, repeat, for, function) may span
+several lines; the prompt changes to '...' until the block is closed.
+
+Meta-commands:
+  :env          list the bindings in the session
+  :ast          show the syntax tree of the last input
+  :load <file>  run a file into the session
+  :history      show the inputs entered so far
+  :reset        clear all bindings
+  :help         show this help
+  :quit         leave the REPL (so does 'exit' or end of input)`
+
+// replSession is the state of one interactive session.
+type replSession struct {
+	out     io.Writer
+	env     *interpreter.Environment
+	lastAST *ast.Program
+	history []string
+	exit    *object.Exit // Set once the program runs 'exit'
+}
+
+// repl starts a Read-Eval-Print Loop for interactive WordLang execution.
+// It returns the exit code: 0, or the code of an 'exit' statement.
+func repl() int {
+	s := &replSession{out: os.Stdout, env: interpreter.NewEnvironment()}
+
+	fmt.Fprintln(s.out, "WordLang REPL. Type :help for help.")
+	var pending []string
+	for {
+		if len(pending) == 0 {
+			fmt.Fprint(s.out, replPrompt)
+		} else {
+			fmt.Fprint(s.out, replContinuePrompt)
+		}
+		// Read through the session's buffered stdin, which 'input' shares
+		line, err := s.env.Session().Stdin.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintln(s.out)
+			return exitOK
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if len(pending) == 0 {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" {
+				continue
+			}
+			if trimmed == "exit" || trimmed == ":quit" {
+				return exitOK
+			}
+			if strings.HasPrefix(trimmed, ":") {
+				s.history = append(s.history, trimmed)
+				s.metaCommand(trimmed)
+				if s.exit != nil {
+					return s.exit.Code
+				}
+				continue
+			}
+		}
+
+		pending = append(pending, line)
+		input := strings.Join(pending, "\n")
+		if incomplete(input) {
+			continue // Keep reading until every block is closed
+		}
+		pending = nil
+
+		s.history = append(s.history, input)
+		s.eval(input, true)
+		if s.exit != nil {
+			return s.exit.Code
+		}
+	}
+}
+
+// incomplete reports whether input ends inside a block or statement, so that
+// the REPL should read more lines before running it.
+func incomplete(input string) bool {
+	_, diagnostics := engine.Parse("", input)
+	for _, d := range diagnostics {
+		if d.Code == diagnostic.CodeUnexpectedEOF {
+			return true
+		}
+	}
+	return false
+}
+
+// eval parses and runs input in the session. With echo set, the value of a
+// trailing expression statement is printed unless it is NULL.
+func (s *replSession) eval(input string, echo bool) {
+	program, diagnostics := engine.Parse("", input)
+	s.lastAST = program
+	if len(diagnostics) != 0 {
+		for _, d := range diagnostics {
+			diagnostic.Render(os.Stderr, d, input)
+		}
+		return
+	}
+
+	result := interpreter.Eval(program, s.env)
+	if result == nil {
+		return
+	}
+	if errObj, ok := result.(*object.Error); ok {
+		for _, d := range errObj.Diagnostics("") {
+			source := input
+			if d.File != "" { // Reported in an imported module
+				content, _ := os.ReadFile(d.File)
+				source = string(content)
+			}
+			diagnostic.Render(os.Stderr, d, source)
+		}
+		return
+	}
+	if exit, ok := result.(*object.Exit); ok {
+		s.exit = exit
+		return
+	}
+
+	if !echo || len(program.Statements) == 0 || result == object.NULL {
+		return
+	}
+	if _, ok := program.Statements[len(program.Statements)-1].(*ast.ExpressionStatement); ok {
+		fmt.Fprintln(s.out, result.Inspect())
+	}
+}
+
+// metaCommand runs one of the ':' commands.
+func (s *replSession) metaCommand(line string) {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case ":help":
+		fmt.Fprintln(s.out, replHelp)
+	case ":env":
+		for _, name := range s.env.Names() {
+			val, _ := s.env.Get(name)
+			fmt.Fprintf(s.out, "%s = %s\n", name, val.Inspect())
+		}
+	case ":ast":
+		if s.lastAST == nil {
+			fmt.Fprintln(s.out, "nothing parsed yet")
+			return
+		}
+		for _, stmt := range s.lastAST.Statements {
+			fmt.Fprintln(s.out, stmt.String())
+		}
+	case ":load":
+		if len(fields) != 2 {
+			fmt.Fprintln(os.Stderr, "usage: :load <file>")
+			return
+		}
+		content, err := os.ReadFile(fields[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading file: %s\n", err)
+			return
+		}
+		file := s.env.File()
+		s.env.SetFile(fields[1]) // Resolve the file's imports relative to it
+		s.eval(string(content), false)
+		s.env.SetFile(file)
+	case ":history":
+		for i, entry := range s.history {
+			fmt.Fprintf(s.out, "%4d  %s\n", i+1, strings.ReplaceAll(entry, "\n", "\n      "))
+		}
+	case ":reset":
+		s.env = interpreter.NewEnvironment()
+		s.lastAST = nil
+		fmt.Fprintln(s.out, "session cleared")
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %s, type :help for help\n", fields[0])
+	}
+}
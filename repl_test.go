@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestIncomplete(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"print 1", false},
+		{"if true then", true},
+		{"if true then\nprint 1\nendif", false},
+		{"if true then\nlet l be list 1 2 end", true},
+		{"if true then\nlet l be list end", true},
+		{"function f\nreturn strings \"a\" end", true},
+		{"function f\nreturn numbers 1 2\nend", false},
+		{"function f\nprint 1\nend\nfor i from 1 to 2 do", true},
+		{"let x be", true},
+		{"let x 5", false},
+		{"endif", false},
+	}
+
+	for _, tt := range tests {
+		if got := incomplete(tt.input); got != tt.want {
+			t.Errorf("%q: got %t, want %t", tt.input, got, tt.want)
+		}
+	}
+}
@@ -36,8 +36,7 @@ endfunction
 call greet "User" "WordLang Learner"
 
 let myList be numbers 1 2 3 4  # Changed "mylist" to "myList"
-#let firstItem be get item at index 0 from myList # 
-let firstItem be item at index 0 myList # need to find a best way
+let firstItem be get item at index 0 from myList
 print firstItem
 
 let isTen be isdefined mynumber # mynumber is not defined, should be false
@@ -18,8 +18,10 @@ const (
 
 	// Identifiers + Literals
 	IDENT  = "IDENT" // e.g., variable names, function names
-	NUMBER = "NUMBER"
+	INT    = "INT"   // 42, 1_000, 0xff, 0b1010
+	FLOAT  = "FLOAT" // 3.14, 1.5e3
 	STRING = "STRING"
+	INTERPOLATED = "INTERPOLATED" // A string containing {expression} parts
 	TRUE   = "TRUE"
 	FALSE  = "FALSE"
 
@@ -42,6 +44,9 @@ const (
 	SUBTRACT = "SUBTRACT"
 	MULTIPLY = "MULTIPLY"
 	DIVIDE   = "DIVIDE"
+	MINUS    = "-"  // Unary negation, '-5'
+	TO       = "TO" // 'add 5 to x'
+	BY       = "BY" // 'mult a by b', 'div a by b'
 	AND      = "AND"
 	OR       = "OR"
 	NOT      = "NOT"
@@ -55,6 +60,7 @@ const (
 	DO       = "DO"
 	END      = "END" // Generic 'end' keyword for blocks
 	LIST     = "LIST"
+	GETITEMATINDEX = "GETITEMATINDEX"
 	FROM       = "FROM"
 	INDEX      = "INDEX"
 	ISDEFINED  = "ISDEFINED"
@@ -64,10 +70,50 @@ const (
 	CONVERTTOSTRING = "CONVERTTOSTRING"
 	BE         = "BE"        // Add BE token type
 	ENDFUNCTION = "ENDFUNCTION" // Add ENDFUNCTION token type
+	IMPORT     = "IMPORT"     // 'from stdio import print'
+	GROUP      = "GROUP"      // 'group ... endgroup' for explicit grouping
+	ENDGROUP   = "ENDGROUP"
+	STOP       = "STOP"   // 'stop' or 'stop loop': break
+	SKIP       = "SKIP"   // 'skip' or 'skip to next': continue
+	NAMED      = "NAMED"  // 'while ... named outer do' labels a loop
+	REPEAT     = "REPEAT" // 'repeat 5 times do ... endrepeat'
+	TIMES      = "TIMES"
+	ENDREPEAT  = "ENDREPEAT"
+	FOR        = "FOR"    // 'for i from 1 to 10 step 2 do ... endfor'
+	STEP       = "STEP"
+	ENDFOR     = "ENDFOR"
+	INCREMENT  = "INCREMENT" // 'increment count by 5'
+	DECREMENT  = "DECREMENT"
+	ITEMATINDEX = "ITEMATINDEX" // 'increment item at index 2 of scores'
+	OF         = "OF"
+	SET        = "SET" // 'set total to 5': assign to an existing binding
+	WHEN       = "WHEN" // 'when x is 1 or 2 ... otherwise ... endwhen'
+	IS         = "IS"
+	BETWEEN    = "BETWEEN"
+	OTHERWISE  = "OTHERWISE"
+	ENDWHEN    = "ENDWHEN"
+	TRY        = "TRY" // 'try ... if it fails as err ... always ... endtry'
+	IFITFAILS  = "IFITFAILS"
+	AS         = "AS"
+	ALWAYS     = "ALWAYS"
+	ENDTRY     = "ENDTRY"
+	RAISE      = "RAISE" // 'raise "message"'
+	STRINGS    = "STRINGS"  // 'strings "a" "b"': a list that only holds strings
+	NUMBERS    = "NUMBERS"  // Whole numbers
+	DECIMALS   = "DECIMALS" // Floats
+	DICTIONARY = "DICTIONARY" // 'dictionary with "alice" as 90 and "bob" as 85'
+	WITH       = "WITH"
+	VALUEFOR   = "VALUEFOR" // 'value for "alice" in scores'
+	HASKEY     = "HASKEY"   // 'scores has key "alice"'
+	REMOVE     = "REMOVE"   // 'remove "bob" from scores', 'remove item at index 0 from names'
+	APPEND     = "APPEND"   // 'append "Dan" to names'
+	INSERT     = "INSERT"   // 'insert "Dan" at index 2 of names'
+	ATINDEX    = "ATINDEX"
 
 
 	// Punctuation (minimal, but we might keep # for comments)
 	COMMENT = "COMMENT"
+	DOCCOMMENT = "DOCCOMMENT" // '## text', kept for the declaration that follows
 	HASH    = "#"
 	SPACE   = "SPACE" // For handling whitespace significance later
 	NEWLINE = "NEWLINE"
@@ -86,11 +132,14 @@ var keywords = map[string]TokenType{
 	"foreach":           FOREACH,
 	"in":                IN,
 	"endforeach":        ENDFOREACH,
+	"print":             PRINT,
 	"input":             INPUT,
 	"add":               ADD,
 	"sub":               SUBTRACT,
 	"mult":              MULTIPLY,
 	"div":               DIVIDE,
+	"to":                TO,
+	"by":                BY,
 	"and":               AND,
 	"or":                OR,
 	"not":               NOT,
@@ -116,6 +165,46 @@ var keywords = map[string]TokenType{
 	"false":             FALSE,
 	"be":                BE,        // Add "be" keyword
 	"endfunction":       ENDFUNCTION, // Add "end function" keyword
+	"import":            IMPORT,
+	"group":             GROUP,
+	"endgroup":          ENDGROUP,
+	"stop":              STOP,
+	"stop loop":         STOP,
+	"skip":              SKIP,
+	"skip to next":      SKIP,
+	"named":             NAMED,
+	"repeat":            REPEAT,
+	"times":             TIMES,
+	"endrepeat":         ENDREPEAT,
+	"for":               FOR,
+	"step":              STEP,
+	"endfor":            ENDFOR,
+	"increment":         INCREMENT,
+	"decrement":         DECREMENT,
+	"item at index":     ITEMATINDEX,
+	"of":                OF,
+	"set":               SET,
+	"when":              WHEN,
+	"between":           BETWEEN,
+	"otherwise":         OTHERWISE,
+	"endwhen":           ENDWHEN,
+	"try":               TRY,
+	"if it fails":       IFITFAILS,
+	"as":                AS,
+	"always":            ALWAYS,
+	"endtry":            ENDTRY,
+	"raise":             RAISE,
+	"strings":           STRINGS,
+	"numbers":           NUMBERS,
+	"decimals":          DECIMALS,
+	"dictionary":        DICTIONARY,
+	"with":              WITH,
+	"value for":         VALUEFOR,
+	"has key":           HASKEY,
+	"remove":            REMOVE,
+	"append":            APPEND,
+	"insert":            INSERT,
+	"at index":          ATINDEX,
 }
 
 // LookupIdent checks if the identifier is a keyword.